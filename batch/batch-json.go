@@ -0,0 +1,44 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+type proofJSON struct {
+	utils.WireHeaderJSON
+	T            string         `json:"T"`
+	GipaKzgProof *gipakzg.Proof `json:"GipaKzgProof"`
+}
+
+// MarshalJSON encodes the proof with hex group elements. The inner proof keeps its own header.
+func (self Proof) MarshalJSON() ([]byte, error) {
+	out := proofJSON{
+		utils.NewWireHeaderJSON(utils.ProofTypeBatch),
+		utils.GTToHex(&self.T),
+		&self.GipaKzgProof,
+	}
+	return json.Marshal(out)
+}
+
+func (self *Proof) UnmarshalJSON(data []byte) error {
+	var in proofJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("Batch Proof: %w", err)
+	}
+	if err := in.Check(utils.ProofTypeBatch); err != nil {
+		return fmt.Errorf("Batch Proof: %w", err)
+	}
+	if in.GipaKzgProof == nil {
+		return fmt.Errorf("Batch Proof: missing GipaKzgProof")
+	}
+	proof := Proof{GipaKzgProof: *in.GipaKzgProof}
+	if err := utils.GTFromHex(&proof.T, in.T); err != nil {
+		return fmt.Errorf("Batch Proof: T: %w", err)
+	}
+	*self = proof
+	return nil
+}
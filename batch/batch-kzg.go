@@ -0,0 +1,155 @@
+package batch
+
+import (
+	"encoding/binary"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Batching of kzg-go single-point openings.
+// KZG1Settings.CheckProofSingle checks e(C - y g, h) == e(Pi, h^alpha - x h).
+// Opening j is laid out with M = 1 as
+//   P_j = C_j - y_j g, Q_j = h, A_j = Pi_j, B_j = h^alpha - x_j h
+// Only A depends on the KZG proofs, thus the verifier needs (C, x, y) and the KZG VK.
+// Since Q_j = h for all j, Verifier.VerifyEdrax computes Z with one pairing.
+// The openings are padded to a power of 2 (at least 2) with the opening of the zero polynomial at 0.
+// The transcript is seeded with the VK and the claims (see openingsTranscript), otherwise the
+// batching challenge r = H(T) would not depend on the claims and compensating evaluations would pass.
+
+// OpeningClaim is the public part of a kzg-go opening: f(X) = Y for the commitment C.
+type OpeningClaim struct {
+	C mcl.G1
+	X mcl.Fr
+	Y mcl.Fr
+}
+
+// Opening is an OpeningClaim with its proof from KZG1Settings.ComputeProofSingle.
+type Opening struct {
+	OpeningClaim
+	Pi mcl.G1
+}
+
+// OpeningsVK is the part of kzg.KZG1Settings needed to check openings: g = PK[0] and VK = [h, h^alpha].
+type OpeningsVK struct {
+	G      mcl.G1
+	H      mcl.G2
+	HAlpha mcl.G2
+}
+
+func NewOpeningsVK(ks *kzg.KZG1Settings) OpeningsVK {
+	return OpeningsVK{G: ks.PK[0], H: ks.VK[0], HAlpha: ks.VK[1]}
+}
+
+// OpeningsSize returns the padded number of openings, which is the size of the batch keys.
+func OpeningsSize(n int) uint64 {
+	mn := utils.NextPowOf2(uint64(n))
+	if mn < 2 {
+		mn = 2
+	}
+	return mn
+}
+
+// OpeningsStatement returns P, Q and B for the claims, padded to OpeningsSize.
+func OpeningsStatement(vk *OpeningsVK, claims []OpeningClaim) ([]mcl.G1, []mcl.G2, []mcl.G2) {
+
+	mn := OpeningsSize(len(claims))
+	P := make([]mcl.G1, mn)
+	Q := make([]mcl.G2, mn)
+	B := make([]mcl.G2, mn)
+
+	var g1Tmp mcl.G1
+	var g2Tmp mcl.G2
+	for j := uint64(0); j < mn; j++ {
+		Q[j] = vk.H
+		if j >= uint64(len(claims)) {
+			P[j].Clear()
+			B[j] = vk.HAlpha
+			continue
+		}
+		mcl.G1Mul(&g1Tmp, &vk.G, &claims[j].Y)
+		mcl.G1Sub(&P[j], &claims[j].C, &g1Tmp)
+		mcl.G2Mul(&g2Tmp, &vk.H, &claims[j].X)
+		mcl.G2Sub(&B[j], &vk.HAlpha, &g2Tmp)
+	}
+	return P, Q, B
+}
+
+// OpeningsWitness returns A, the KZG proofs padded to OpeningsSize.
+func OpeningsWitness(openings []Opening) []mcl.G1 {
+
+	mn := OpeningsSize(len(openings))
+	A := make([]mcl.G1, mn)
+	for j := uint64(0); j < mn; j++ {
+		if j >= uint64(len(openings)) {
+			A[j].Clear()
+			continue
+		}
+		A[j] = openings[j].Pi
+	}
+	return A
+}
+
+// OpeningsLayout returns P, Q, A and B for the openings, padded to OpeningsSize.
+func OpeningsLayout(vk *OpeningsVK, openings []Opening) ([]mcl.G1, []mcl.G2, []mcl.G1, []mcl.G2) {
+
+	claims := make([]OpeningClaim, len(openings))
+	for j := range openings {
+		claims[j] = openings[j].OpeningClaim
+	}
+	P, Q, B := OpeningsStatement(vk, claims)
+	return P, Q, OpeningsWitness(openings), B
+}
+
+// openingsTranscript is H("Batch KZG openings" || VK || n || claims), the initial transcript of
+// ProveOpenings and VerifyOpenings.
+func openingsTranscript(vk *OpeningsVK, claims []OpeningClaim) [32]byte {
+	data := make([]byte, 0)
+	data = append(data, []byte("Batch KZG openings")...)
+	data = append(data, vk.G.Serialize()...)
+	data = append(data, vk.H.Serialize()...)
+	data = append(data, vk.HAlpha.Serialize()...)
+	var n [8]byte
+	binary.LittleEndian.PutUint64(n[:], uint64(len(claims)))
+	data = append(data, n[:]...)
+	for j := range claims {
+		data = append(data, claims[j].C.Serialize()...)
+		data = append(data, claims[j].X.Serialize()...)
+		data = append(data, claims[j].Y.Serialize()...)
+	}
+	return blake2b.Sum256(data)
+}
+
+// ProveOpenings computes one batch proof for all the openings.
+// ck, kzg1 and kzg2 are the batch keys of size OpeningsSize(len(openings)), not the keys of the openings.
+func ProveOpenings(ck *cm.Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, vk *OpeningsVK, openings []Opening) Proof {
+
+	_, _, A, B := OpeningsLayout(vk, openings)
+	mn := uint64(len(A))
+	prover := Prover{}
+	prover.Init(1, uint32(mn), mn, ck, kzg1, kzg2, A, B)
+	claims := make([]OpeningClaim, len(openings))
+	for j := range openings {
+		claims[j] = openings[j].OpeningClaim
+	}
+	prover.Prover.Transcript = openingsTranscript(vk, claims)
+	return prover.Prove()
+}
+
+// VerifyOpenings checks a proof from ProveOpenings against the claims.
+// W, kzg1 and kzg2 are the batch keys. vk is the KZG VK of the openings.
+func VerifyOpenings(W []mcl.G1, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, vk *OpeningsVK, claims []OpeningClaim, proof Proof) bool {
+
+	P, Q, B := OpeningsStatement(vk, claims)
+	mn := uint64(len(B))
+	if uint64(len(W)) != mn || uint64(len(kzg1.PK)) != 2*mn-1 || uint64(len(kzg2.PK)) != 2*mn-1 {
+		return false
+	}
+	verifier := Verifier{}
+	verifier.Init(1, uint32(mn), mn, W, kzg1, kzg2, P, Q, B)
+	verifier.Verifier.Transcript = openingsTranscript(vk, claims)
+	return verifier.VerifyEdrax(proof)
+}
@@ -1,9 +1,13 @@
 package batch
 
 import (
+	"context"
+	"time"
+
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/cm"
 	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/knownb"
 	"github.com/hyperproofs/gipa-go/utils"
 	"github.com/hyperproofs/kzg-go/kzg"
 	"golang.org/x/crypto/blake2b"
@@ -28,16 +32,49 @@ type Prover struct {
 // }
 
 func (self *Prover) Prove() Proof {
+	proof, _ := self.ProveContext(context.Background())
+	return proof
+}
+
+// ProveContext is Prove, which returns ctx.Err() once ctx is done, see gipakzg.Prover.ProveContext.
+// The progress of T is reported as utils.PhaseCommit to self.Prover.Progress.
+func (self *Prover) ProveContext(ctx context.Context) (Proof, error) {
 
 	proof := Proof{}
 
+	start := time.Now()
+	T, err := utils.InnerProdsContext(ctx, [][]mcl.G1{self.Prover.A}, [][]mcl.G2{self.Prover.Ck.V}, self.Prover.Workers)
+	if err != nil {
+		return Proof{}, err
+	}
+	proof.T = T[0]
+	self.Prover.Progress.Report(-1, self.MN, utils.PhaseCommit, start)
+
+	r := self.FiatShamir(self.Prover.Transcript[:], proof.T)
+	m := int(self.M)
+	self.Prover.B = utils.G2VecRandExpo(self.Prover.B, r, m)
+	proof.GipaKzgProof, err = self.Prover.ProveContext(ctx)
+	if err != nil {
+		return Proof{}, err
+	}
+	return proof, nil
+}
+
+// ProveKnownB is Prove with the single-sided argument of knownb.
+func (self *Prover) ProveKnownB() KnownBProof {
+
+	proof := KnownBProof{}
+
 	T := utils.InnerProd(self.Prover.A, self.Prover.Ck.V)
 	proof.T = T
 
 	r := self.FiatShamir(self.Prover.Transcript[:], proof.T)
 	m := int(self.M)
-	self.Prover.B = utils.G2VecRandExpo(self.Prover.B, r, m)
-	proof.GipaKzgProof = self.Prover.Prove()
+	B := utils.G2VecRandExpo(self.Prover.B, r, m)
+
+	prover := knownb.Prover{}
+	prover.Init(self.MN, &self.Prover.Ck, &self.Prover.KZG2, self.Prover.A, B)
+	proof.KnownB = prover.Prove()
 	return proof
 }
 
@@ -81,4 +118,6 @@ func (self *Prover) Clone(prover *Prover) {
 		prover.Prover.A,
 		prover.Prover.B,
 	)
+	self.Prover.Workers = prover.Prover.Workers
+	self.Prover.Progress = prover.Prover.Progress
 }
@@ -3,9 +3,17 @@ package batch
 import (
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/knownb"
 )
 
 type Proof struct {
 	T            mcl.GT
 	GipaKzgProof gipakzg.Proof
 }
+
+// KnownBProof is the batch proof where only A is committed, see knownb.
+// The verifier already knows B, thus the commitment to B and the folding of ck.W are dropped.
+type KnownBProof struct {
+	T      mcl.GT
+	KnownB knownb.Proof
+}
@@ -1,9 +1,15 @@
 package batch
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
 	"github.com/hyperproofs/gipa-go/utils"
 	"github.com/jinzhu/copier"
 )
@@ -33,3 +39,159 @@ func TestBatching(t *testing.T) {
 		}
 	})
 }
+
+func TestBatchingProveContext(t *testing.T) {
+
+	M := uint32(1) << 4
+	N := uint32(1) << 3
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaBatchTestSetup(M, N, alpha, beta, g, h)
+	var cancelled Prover
+	cancelled.Clone(&prover)
+	var phases []utils.Progress
+	prover.Prover.Progress = func(p utils.Progress) { phases = append(phases, p) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proof, err := prover.ProveContext(ctx)
+	if err != nil {
+		t.Fatalf("Batching ProveContext: %v", err)
+	}
+	if len(phases) == 0 || phases[0].Phase != utils.PhaseCommit || phases[0].M != prover.MN {
+		t.Errorf("Batching ProveContext: T was not reported first")
+	}
+	if !verifier.Verify(proof) {
+		t.Errorf("Batching ProveContext: Proof did not verify")
+	}
+
+	cancel()
+	if _, err := cancelled.ProveContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Batching ProveContext: Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestBatchingKnownB(t *testing.T) {
+
+	M := uint32(1) << 4
+	N := uint32(1) << 5
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaBatchTestSetup(M, N, alpha, beta, g, h)
+	var verifierCopy Verifier
+	copier.Copy(&verifierCopy, &verifier)
+	proof := prover.ProveKnownB()
+	t.Run(fmt.Sprintf("%d/BatchingKnownB;", M), func(t *testing.T) {
+		if !verifier.VerifyKnownB(proof) {
+			t.Errorf("Batching Known B Test: Failed")
+		}
+	})
+
+	proof.T = utils.InnerProd(verifierCopy.P, verifierCopy.Q)
+	t.Run(fmt.Sprintf("%d/BatchingKnownBReject;", M), func(t *testing.T) {
+		if verifierCopy.VerifyKnownB(proof) {
+			t.Errorf("Batching Known B Test: Wrong T accepted")
+		}
+	})
+}
+
+func TestBatchingEncoding(t *testing.T) {
+
+	M := uint32(1) << 2
+	N := uint32(1) << 3
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaBatchTestSetup(M, N, alpha, beta, g, h)
+	proof := prover.Prove()
+
+	data, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Batching Encoding: Marshal failed: %v", err)
+	}
+
+	var decoded Proof
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Batching Encoding: Unmarshal failed: %v", err)
+	}
+	again, _ := decoded.MarshalBinary()
+	if !bytes.Equal(data, again) {
+		t.Errorf("Batching Encoding: Round trip changed the bytes")
+	}
+	if !verifier.Verify(decoded) {
+		t.Errorf("Batching Encoding: Decoded proof did not verify")
+	}
+
+	jsonData, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatalf("Batching JSON: Marshal failed: %v", err)
+	}
+	var decodedJSON Proof
+	if err := json.Unmarshal(jsonData, &decodedJSON); err != nil {
+		t.Fatalf("Batching JSON: Unmarshal failed: %v", err)
+	}
+	fromJSON, _ := decodedJSON.MarshalBinary()
+	if !bytes.Equal(data, fromJSON) {
+		t.Errorf("Batching JSON: Binary form changed after JSON round trip")
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, utils.ErrTruncated) {
+		t.Errorf("Batching Encoding: Truncation not detected: %v", err)
+	}
+}
+
+func TestBatchingKZGOpenings(t *testing.T) {
+
+	n := 5
+	mn := OpeningsSize(n)
+	alpha, beta, g, h := utils.RunMPC()
+	ck, kzg1, kzg2 := cm.IPPSetupKZG(mn, alpha, beta, g, h)
+
+	// The openings use kzg1 as well, any KZG1Settings works.
+	vk := NewOpeningsVK(kzg1)
+	openings := make([]Opening, n)
+	claims := make([]OpeningClaim, n)
+	for j := range openings {
+		poly := make([]mcl.Fr, len(kzg1.PK))
+		for i := range poly {
+			poly[i].Random()
+		}
+		openings[j].C = *kzg1.CommitToPoly(poly)
+		openings[j].X.Random()
+		Pi, y := kzg1.ComputeProofSingle(poly, &openings[j].X)
+		openings[j].Pi = *Pi
+		openings[j].Y = *y
+		claims[j] = openings[j].OpeningClaim
+		if !kzg1.CheckProofSingle(&openings[j].C, Pi, &openings[j].X, y) {
+			t.Fatalf("Batching KZG: Opening %d is invalid", j)
+		}
+	}
+
+	proof := ProveOpenings(ck, kzg1, kzg2, &vk, openings)
+	t.Run(fmt.Sprintf("%d/BatchingKZG;", n), func(t *testing.T) {
+		if !VerifyOpenings(ck.W, kzg1, kzg2, &vk, claims, proof) {
+			t.Errorf("Batching KZG Test: Failed")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/BatchingKZGReject;", n), func(t *testing.T) {
+		wrong := append([]OpeningClaim{}, claims...)
+		mcl.FrAdd(&wrong[n-1].Y, &wrong[n-1].Y, &alpha)
+		if VerifyOpenings(ck.W, kzg1, kzg2, &vk, wrong, proof) {
+			t.Errorf("Batching KZG Test: Wrong evaluation accepted")
+		}
+		if VerifyOpenings(ck.W, kzg1, kzg2, &vk, claims[:n-1], proof) {
+			t.Errorf("Batching KZG Test: Dropped claim accepted")
+		}
+
+		// Without the claims in the transcript, r = H(0 || T) is known once the proofs are fixed.
+		// Claim j is weighted by r^{2j}, thus y_0 + delta and y_1 - delta r^{-2} cancel out.
+		var forger Prover
+		r := forger.FiatShamir(make([]byte, 32), proof.T)
+		var delta, shift mcl.Fr
+		delta.Random()
+		mcl.FrSqr(&shift, &r)
+		mcl.FrInv(&shift, &shift)
+		mcl.FrMul(&shift, &shift, &delta)
+		forged := append([]OpeningClaim{}, claims...)
+		mcl.FrAdd(&forged[0].Y, &forged[0].Y, &delta)
+		mcl.FrSub(&forged[1].Y, &forged[1].Y, &shift)
+		if VerifyOpenings(ck.W, kzg1, kzg2, &vk, forged, proof) {
+			t.Errorf("Batching KZG Test: Compensating claims accepted")
+		}
+	})
+}
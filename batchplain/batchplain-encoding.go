@@ -0,0 +1,85 @@
+package batchplain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// Binary encoding of the plain batch proof
+// header (utils.WriteWireHeader) | T | GipaProof (with its own header)
+// The rounds in the outer header must match the inner proof.
+
+// MarshalBinary encodes the proof along with the wire header.
+func (self *Proof) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := self.encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a proof written by MarshalBinary.
+// It rejects truncated input and trailing bytes.
+func (self *Proof) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if err := self.decode(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("BatchPlain Proof: %w: %d bytes", utils.ErrTrailingBytes, r.Len())
+	}
+	return nil
+}
+
+// WriteTo writes the encoded proof to w.
+func (self *Proof) WriteTo(w io.Writer) (int64, error) {
+	data, err := self.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads exactly one encoded proof from r.
+// Unlike UnmarshalBinary, it does not look past the end of the proof. Thus, proofs can be streamed back to back.
+func (self *Proof) ReadFrom(r io.Reader) (int64, error) {
+	cr := utils.CountingReader{R: r}
+	err := self.decode(&cr)
+	return cr.N, err
+}
+
+func (self *Proof) encode(w io.Writer) error {
+
+	if err := utils.WriteWireHeader(w, utils.ProofTypeBatchPlain, len(self.GipaProof.L)); err != nil {
+		return err
+	}
+	if err := utils.WriteGT(w, &self.T); err != nil {
+		return err
+	}
+	_, err := self.GipaProof.WriteTo(w)
+	return err
+}
+
+func (self *Proof) decode(r io.Reader) error {
+
+	rounds, err := utils.ReadWireHeader(r, utils.ProofTypeBatchPlain)
+	if err != nil {
+		return fmt.Errorf("BatchPlain Proof: %w", err)
+	}
+	proof := Proof{}
+	if err := utils.ReadGT(r, &proof.T); err != nil {
+		return fmt.Errorf("BatchPlain Proof: T: %w", err)
+	}
+	if _, err := proof.GipaProof.ReadFrom(r); err != nil {
+		return fmt.Errorf("BatchPlain Proof: %w", err)
+	}
+	if len(proof.GipaProof.L) != rounds {
+		return fmt.Errorf("BatchPlain Proof: header has %d rounds, but inner proof has %d", rounds, len(proof.GipaProof.L))
+	}
+	*self = proof
+	return nil
+}
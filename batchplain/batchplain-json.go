@@ -0,0 +1,44 @@
+package batchplain
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperproofs/gipa-go/gipa"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+type proofJSON struct {
+	utils.WireHeaderJSON
+	T         string      `json:"T"`
+	GipaProof *gipa.Proof `json:"GipaProof"`
+}
+
+// MarshalJSON encodes the proof with hex group elements. The inner proof keeps its own header.
+func (self Proof) MarshalJSON() ([]byte, error) {
+	out := proofJSON{
+		utils.NewWireHeaderJSON(utils.ProofTypeBatchPlain),
+		utils.GTToHex(&self.T),
+		&self.GipaProof,
+	}
+	return json.Marshal(out)
+}
+
+func (self *Proof) UnmarshalJSON(data []byte) error {
+	var in proofJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("BatchPlain Proof: %w", err)
+	}
+	if err := in.Check(utils.ProofTypeBatchPlain); err != nil {
+		return fmt.Errorf("BatchPlain Proof: %w", err)
+	}
+	if in.GipaProof == nil {
+		return fmt.Errorf("BatchPlain Proof: missing GipaProof")
+	}
+	proof := Proof{GipaProof: *in.GipaProof}
+	if err := utils.GTFromHex(&proof.T, in.T); err != nil {
+		return fmt.Errorf("BatchPlain Proof: T: %w", err)
+	}
+	*self = proof
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/cm"
 	"github.com/hyperproofs/gipa-go/gipa"
+	"github.com/hyperproofs/gipa-go/knownb"
 	"github.com/hyperproofs/gipa-go/utils"
 	"golang.org/x/crypto/blake2b"
 )
@@ -17,6 +18,8 @@ type Verifier struct {
 	B        []mcl.G2
 	P        []mcl.G1
 	Q        []mcl.G2
+
+	PreparedQ *utils.PreparedVerifyingKey // Q with its Miller-loop lines, created on first use. Clone shares it.
 }
 
 func (self *Verifier) FiatShamir(Transcript []byte, T mcl.GT) mcl.Fr {
@@ -56,7 +59,7 @@ func (self *Verifier) Verify(proof Proof) bool {
 	self.B = utils.G2VecRandExpo(self.B, r, m)                 // For P vector, M == 1 there is only one pairing on the lhs
 	self.P = utils.G1VecRandExpo(self.P, r, 1)                 // For P vector, M == 1 there is only one pairing on the lhs
 	U := utils.InnerProd(self.W, self.B)
-	Z := utils.InnerProd(self.P, self.Q) // In Edrax we can get away with just one pairing
+	Z := self.preparedQ().InnerProd(self.P) // In Edrax we can get away with just one pairing
 	com := cm.Com{}
 	com.Com[0] = proof.T
 	com.Com[1] = U
@@ -97,9 +100,8 @@ func (self *Verifier) VerifyEdrax(proof Proof) bool {
 	}
 
 	pTemp := []mcl.G1{Psum}
-	qTemp := []mcl.G2{self.Q[0]}
 
-	Z := utils.InnerProd(pTemp, qTemp) // In Edrax we can get away with just one pairing
+	Z := self.preparedQ().InnerProd(pTemp) // In Edrax we can get away with just one pairing, with Q[0]. Only Q[0] is prepared.
 
 	com := cm.Com{}
 	com.Com[0] = proof.T
@@ -110,6 +112,23 @@ func (self *Verifier) VerifyEdrax(proof Proof) bool {
 	return status
 }
 
+// VerifyKnownB checks a proof of ProveKnownB. Z is computed as in Verify, and U is not needed.
+func (self *Verifier) VerifyKnownB(proof KnownBProof) bool {
+
+	m := int(self.M)
+	r := self.FiatShamir(self.Verifier.Transcript[:], proof.T)
+	self.B = utils.G2VecRandExpo(self.B, r, m)
+	self.P = utils.G1VecRandExpo(self.P, r, 1)
+	Z := self.preparedQ().InnerProd(self.P)
+	com := knownb.Com{}
+	com.Com[0] = proof.T
+	com.Com[1] = Z
+	verifier := knownb.Verifier{}
+	verifier.InitPlain(self.MN, self.Verifier.Ck.V, com, self.B)
+	status := verifier.Verify(proof.KnownB)
+	return status
+}
+
 func (self *Verifier) Init(M uint32, N uint32, MN uint64, ck *cm.Ck, P []mcl.G1, Q []mcl.G2, B []mcl.G2) {
 
 	utils.InstanceSizeChecker(MN, "BatchPlain Verifier Init: M is not a power of 2")
@@ -137,14 +156,35 @@ func (self *Verifier) Init(M uint32, N uint32, MN uint64, ck *cm.Ck, P []mcl.G1,
 	copy(self.B, B)
 }
 
+// InitPrepared is Init with a PreparedVerifyingKey of Q, which may be shared by many verifiers.
+// A nil preparedQ is created on first use, as in Init.
+func (self *Verifier) InitPrepared(M uint32, N uint32, MN uint64, ck *cm.Ck, P []mcl.G1, Q []mcl.G2, B []mcl.G2,
+	preparedQ *utils.PreparedVerifyingKey) {
+
+	if preparedQ != nil && !preparedQ.Matches(Q) {
+		panic("BatchPlain Verifier InitPrepared: Prepared key differs from Q")
+	}
+	self.Init(M, N, MN, ck, P, Q, B)
+	self.PreparedQ = preparedQ
+}
+
+// preparedQ returns self.PreparedQ, and creates it on first use.
+func (self *Verifier) preparedQ() *utils.PreparedVerifyingKey {
+	if self.PreparedQ == nil {
+		self.PreparedQ = utils.NewPreparedVerifyingKey(self.Q)
+	}
+	return self.PreparedQ
+}
+
 // Use this only if you need to deepcopy
 func (self *Verifier) Clone(verifier *Verifier) {
-	self.Init(
+	self.InitPrepared(
 		verifier.M,
 		verifier.N,
 		verifier.MN,
 		&verifier.Verifier.Ck,
 		verifier.P,
 		verifier.Q,
-		verifier.B)
+		verifier.B,
+		verifier.PreparedQ)
 }
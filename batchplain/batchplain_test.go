@@ -1,6 +1,10 @@
 package batchplain
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -33,3 +37,97 @@ func TestBatchingPlain(t *testing.T) {
 		}
 	})
 }
+
+func TestBatchingPlainProveContext(t *testing.T) {
+
+	M := uint32(1) << 4
+	N := uint32(1) << 3
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaBatchPlainTestSetup(M, N, alpha, beta, g, h)
+	var cancelled Prover
+	cancelled.Clone(&prover)
+	var phases []utils.Progress
+	prover.Prover.Progress = func(p utils.Progress) { phases = append(phases, p) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proof, err := prover.ProveContext(ctx)
+	if err != nil {
+		t.Fatalf("BatchingPlain ProveContext: %v", err)
+	}
+	if len(phases) == 0 || phases[0].Phase != utils.PhaseCommit || phases[0].M != prover.MN {
+		t.Errorf("BatchingPlain ProveContext: T was not reported first")
+	}
+	if !verifier.Verify(proof) {
+		t.Errorf("BatchingPlain ProveContext: Proof did not verify")
+	}
+
+	cancel()
+	if _, err := cancelled.ProveContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("BatchingPlain ProveContext: Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestBatchingPlainKnownB(t *testing.T) {
+
+	M := uint32(1) << 4
+	N := uint32(1) << 5
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaBatchPlainTestSetup(M, N, alpha, beta, g, h)
+	var verifierCopy Verifier
+	copier.Copy(&verifierCopy, &verifier)
+	proof := prover.ProveKnownB()
+	t.Run(fmt.Sprintf("%d/BatchingPlainKnownB;", M), func(t *testing.T) {
+		if !verifier.VerifyKnownB(proof) {
+			t.Errorf("BatchingPlain Known B Test: Failed")
+		}
+	})
+
+	proof.T = utils.InnerProd(verifierCopy.P, verifierCopy.Q)
+	t.Run(fmt.Sprintf("%d/BatchingPlainKnownBReject;", M), func(t *testing.T) {
+		if verifierCopy.VerifyKnownB(proof) {
+			t.Errorf("BatchingPlain Known B Test: Wrong T accepted")
+		}
+	})
+}
+
+func TestBatchingPlainEncoding(t *testing.T) {
+
+	M := uint32(1) << 2
+	N := uint32(1) << 3
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaBatchPlainTestSetup(M, N, alpha, beta, g, h)
+	proof := prover.Prove()
+
+	data, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatalf("BatchingPlain Encoding: Marshal failed: %v", err)
+	}
+
+	var decoded Proof
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("BatchingPlain Encoding: Unmarshal failed: %v", err)
+	}
+	again, _ := decoded.MarshalBinary()
+	if !bytes.Equal(data, again) {
+		t.Errorf("BatchingPlain Encoding: Round trip changed the bytes")
+	}
+	if !verifier.Verify(decoded) {
+		t.Errorf("BatchingPlain Encoding: Decoded proof did not verify")
+	}
+
+	jsonData, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatalf("BatchingPlain JSON: Marshal failed: %v", err)
+	}
+	var decodedJSON Proof
+	if err := json.Unmarshal(jsonData, &decodedJSON); err != nil {
+		t.Fatalf("BatchingPlain JSON: Unmarshal failed: %v", err)
+	}
+	fromJSON, _ := decodedJSON.MarshalBinary()
+	if !bytes.Equal(data, fromJSON) {
+		t.Errorf("BatchingPlain JSON: Binary form changed after JSON round trip")
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, utils.ErrTruncated) {
+		t.Errorf("BatchingPlain Encoding: Truncation not detected: %v", err)
+	}
+}
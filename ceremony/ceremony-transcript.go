@@ -0,0 +1,242 @@
+package ceremony
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hyperproofs/gipa-go/utils"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Transcript layout (little endian):
+// magic "GIPACRMN" (8) | version (1) | curve (1) | reserved (2) | MN (8) | count (4) | G | H | initial digest (32)
+// then each contribution:
+// name length (1) | name | prev digest (32) | digest (32) | AlphaG1 | BetaG2 | Alpha proof | Beta proof
+// An update proof is G1 | G2 | R | Z.
+
+const (
+	TranscriptMagic   = "GIPACRMN"
+	TranscriptVersion = 1
+	maxContributions  = 1 << 20
+)
+
+// SaveTranscript writes t to folderPath/TRANSCRIPT.data.
+func SaveTranscript(folderPath string, t *Transcript) error {
+
+	if err := os.MkdirAll(folderPath, os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(folderPath + "/" + TranscriptFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	if err := WriteTranscript(w, t); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// LoadTranscript reads folderPath/TRANSCRIPT.data. The contributions are not verified, see Verify.
+func LoadTranscript(folderPath string) (*Transcript, error) {
+
+	fileName := folderPath + "/" + TranscriptFile
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	t, err := ReadTranscript(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	if _, err := r.ReadByte(); err != io.EOF {
+		return nil, fmt.Errorf("%s: %w", fileName, utils.ErrTrailingBytes)
+	}
+	return t, nil
+}
+
+// WriteTranscript encodes t in the layout above.
+func WriteTranscript(w io.Writer, t *Transcript) error {
+
+	header := make([]byte, 24)
+	copy(header, TranscriptMagic)
+	header[8] = TranscriptVersion
+	header[9] = utils.CurveBLS12_381
+	binary.LittleEndian.PutUint64(header[12:], t.MN)
+	binary.LittleEndian.PutUint32(header[20:], uint32(len(t.Contributions)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if err := utils.WriteG1(w, &t.G); err != nil {
+		return err
+	}
+	if err := utils.WriteG2(w, &t.H); err != nil {
+		return err
+	}
+	if err := writeDigest(w, t.InitialDigest); err != nil {
+		return err
+	}
+	for i := range t.Contributions {
+		if err := writeContribution(w, &t.Contributions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadTranscript decodes one transcript from r.
+func ReadTranscript(r io.Reader) (*Transcript, error) {
+
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("reading header: %w", utils.ErrTruncated)
+	}
+	if string(header[:8]) != TranscriptMagic {
+		return nil, fmt.Errorf("not a ceremony transcript, magic is %x", header[:8])
+	}
+	if header[8] != TranscriptVersion {
+		return nil, fmt.Errorf("unsupported version %d", header[8])
+	}
+	if header[9] != utils.CurveBLS12_381 {
+		return nil, fmt.Errorf("unsupported curve %d", header[9])
+	}
+	t := &Transcript{MN: binary.LittleEndian.Uint64(header[12:])}
+	if t.MN < 2 || !utils.IsPow2(t.MN) {
+		return nil, fmt.Errorf("invalid size %d", t.MN)
+	}
+	count := binary.LittleEndian.Uint32(header[20:])
+	if count > maxContributions {
+		return nil, fmt.Errorf("invalid number of contributions %d", count)
+	}
+
+	if err := utils.ReadG1(r, &t.G); err != nil {
+		return nil, fmt.Errorf("G: %w", err)
+	}
+	if err := utils.ReadG2(r, &t.H); err != nil {
+		return nil, fmt.Errorf("H: %w", err)
+	}
+	var err error
+	if t.InitialDigest, err = readDigest(r); err != nil {
+		return nil, fmt.Errorf("initial digest: %w", err)
+	}
+	t.Contributions = make([]Contribution, count)
+	for i := range t.Contributions {
+		if err := readContribution(r, &t.Contributions[i]); err != nil {
+			return nil, fmt.Errorf("contribution %d: %w", i, err)
+		}
+	}
+	return t, nil
+}
+
+func writeContribution(w io.Writer, c *Contribution) error {
+
+	if len(c.Name) > MaxNameLength {
+		return fmt.Errorf("ceremony: name is longer than %d bytes", MaxNameLength)
+	}
+	if _, err := w.Write(append([]byte{byte(len(c.Name))}, c.Name...)); err != nil {
+		return err
+	}
+	if err := writeDigest(w, c.PrevDigest); err != nil {
+		return err
+	}
+	if err := writeDigest(w, c.Digest); err != nil {
+		return err
+	}
+	if err := utils.WriteG1(w, &c.AlphaG1); err != nil {
+		return err
+	}
+	if err := utils.WriteG2(w, &c.BetaG2); err != nil {
+		return err
+	}
+	if err := writeUpdateProof(w, &c.Alpha); err != nil {
+		return err
+	}
+	return writeUpdateProof(w, &c.Beta)
+}
+
+func readContribution(r io.Reader, c *Contribution) error {
+
+	length := make([]byte, 1)
+	if _, err := io.ReadFull(r, length); err != nil {
+		return fmt.Errorf("name: %w", utils.ErrTruncated)
+	}
+	name := make([]byte, length[0])
+	if _, err := io.ReadFull(r, name); err != nil {
+		return fmt.Errorf("name: %w", utils.ErrTruncated)
+	}
+	c.Name = string(name)
+
+	var err error
+	if c.PrevDigest, err = readDigest(r); err != nil {
+		return fmt.Errorf("previous digest: %w", err)
+	}
+	if c.Digest, err = readDigest(r); err != nil {
+		return fmt.Errorf("digest: %w", err)
+	}
+	if err := utils.ReadG1(r, &c.AlphaG1); err != nil {
+		return fmt.Errorf("AlphaG1: %w", err)
+	}
+	if err := utils.ReadG2(r, &c.BetaG2); err != nil {
+		return fmt.Errorf("BetaG2: %w", err)
+	}
+	if err := readUpdateProof(r, &c.Alpha); err != nil {
+		return fmt.Errorf("alpha proof: %w", err)
+	}
+	if err := readUpdateProof(r, &c.Beta); err != nil {
+		return fmt.Errorf("beta proof: %w", err)
+	}
+	return nil
+}
+
+func writeUpdateProof(w io.Writer, proof *UpdateProof) error {
+	if err := utils.WriteG1(w, &proof.G1); err != nil {
+		return err
+	}
+	if err := utils.WriteG2(w, &proof.G2); err != nil {
+		return err
+	}
+	if err := utils.WriteG1(w, &proof.R); err != nil {
+		return err
+	}
+	return utils.WriteFr(w, &proof.Z)
+}
+
+func readUpdateProof(r io.Reader, proof *UpdateProof) error {
+	if err := utils.ReadG1(r, &proof.G1); err != nil {
+		return err
+	}
+	if err := utils.ReadG2(r, &proof.G2); err != nil {
+		return err
+	}
+	if err := utils.ReadG1(r, &proof.R); err != nil {
+		return err
+	}
+	return utils.ReadFr(r, &proof.Z)
+}
+
+func writeDigest(w io.Writer, digest []byte) error {
+	if len(digest) != blake2b.Size256 {
+		return fmt.Errorf("ceremony: digest is %d bytes, expected %d", len(digest), blake2b.Size256)
+	}
+	_, err := w.Write(digest)
+	return err
+}
+
+func readDigest(r io.Reader) ([]byte, error) {
+	digest := make([]byte, blake2b.Size256)
+	if _, err := io.ReadFull(r, digest); err != nil {
+		return nil, utils.ErrTruncated
+	}
+	return digest, nil
+}
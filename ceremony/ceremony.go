@@ -0,0 +1,391 @@
+package ceremony
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sync"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Updatable setup for the GIPA+KZG keys of cm.IPPSetupKZG.
+//
+// The ceremony starts from alpha = beta = 1 on generators derived with HashAndMapTo, thus nobody knows a trapdoor.
+// Contributor j samples fresh x_j and y_j and updates the current keys in place:
+// kzg1.PK[i] *= x_j^i, kzg1.VK[1] *= x_j, kzg2.PK[i] *= y_j^i, kzg2.VK[1] *= y_j and ck is re-derived.
+// After n contributions, alpha = x_1...x_n and beta = y_1...y_n, which are unknown as long as one contributor
+// destroyed their multipliers.
+//
+// Each contribution publishes g^x, h^x and a Schnorr proof of knowledge of x (and the same for y),
+// bound to the digest of the keys it updated. Verify replays the chain of contributions on the first powers
+// and then checks the structure of the final keys with cm.ValidateKeys.
+//
+// A ceremony folder holds the current keys (cm.KeyContainerFile) and the transcript (TranscriptFile).
+// Every step reads one folder and writes another, thus participants can work on air-gapped machines.
+
+const (
+	TranscriptFile = "TRANSCRIPT.data"
+	MaxNameLength  = 255
+)
+
+var (
+	generatorG1Seed = []byte("gipa-go ceremony G1 generator")
+	generatorG2Seed = []byte("gipa-go ceremony G2 generator")
+)
+
+// UpdateProof shows that a trapdoor was multiplied by a secret x known to the contributor.
+// G1 = g^x, G2 = h^x and (R, Z) is a Schnorr proof of knowledge of x with respect to g.
+type UpdateProof struct {
+	G1 mcl.G1
+	G2 mcl.G2
+	R  mcl.G1
+	Z  mcl.Fr
+}
+
+// Contribution is one entry of the transcript.
+// AlphaG1 = g^alpha and BetaG2 = h^beta are the first powers after the update.
+type Contribution struct {
+	Name       string
+	PrevDigest []byte // Digest of the key container which was updated
+	Digest     []byte // Digest of the key container which was produced
+	AlphaG1    mcl.G1
+	BetaG2     mcl.G2
+	Alpha      UpdateProof
+	Beta       UpdateProof
+}
+
+// Transcript lists the contributions in order.
+type Transcript struct {
+	MN            uint64
+	G             mcl.G1
+	H             mcl.G2
+	InitialDigest []byte
+	Contributions []Contribution
+}
+
+// ContributionError reports which contribution breaks the chain.
+type ContributionError struct {
+	Index  int
+	Name   string
+	Reason string
+}
+
+func (e *ContributionError) Error() string {
+	return fmt.Sprintf("ceremony: contribution %d (%q): %s", e.Index, e.Name, e.Reason)
+}
+
+// Generators returns the nothing-up-my-sleeve generators of the ceremony.
+func Generators() (mcl.G1, mcl.G2) {
+	var g mcl.G1
+	var h mcl.G2
+	if err := g.HashAndMapTo(generatorG1Seed); err != nil {
+		panic(err)
+	}
+	if err := h.HashAndMapTo(generatorG2Seed); err != nil {
+		panic(err)
+	}
+	return g, h
+}
+
+// initialKeys returns the keys for alpha = beta = 1.
+func initialKeys(mn uint64, g mcl.G1, h mcl.G2) (*cm.Ck, *kzg.KZG1Settings, *kzg.KZG2Settings) {
+	var one mcl.Fr
+	one.SetInt64(1)
+	return cm.IPPSetupKZG(mn, one, one, g, h)
+}
+
+// Init starts a ceremony for instances of size mn. It writes the initial keys and an empty transcript to folderPath.
+func Init(folderPath string, mn uint64) error {
+
+	if mn < 2 || !utils.IsPow2(mn) {
+		return fmt.Errorf("ceremony: size %d is not a power of 2 larger than 1", mn)
+	}
+	g, h := Generators()
+	ck, kzg1, kzg2 := initialKeys(mn, g, h)
+	digest, err := cm.SaveKeyContainerDigest(folderPath, ck, kzg1, kzg2)
+	if err != nil {
+		return err
+	}
+	t := Transcript{MN: mn, G: g, H: h, InitialDigest: digest}
+	return SaveTranscript(folderPath, &t)
+}
+
+// Head returns the digest of the keys the next contribution has to update.
+func (self *Transcript) Head() []byte {
+	if len(self.Contributions) == 0 {
+		return self.InitialDigest
+	}
+	return self.Contributions[len(self.Contributions)-1].Digest
+}
+
+// Contribute reads the ceremony in inFolder, applies fresh multipliers and writes the result to outFolder.
+// The multipliers are cleared before returning. The new contribution is returned so that it can be published.
+// inFolder is not verified, run Verify on it first.
+func Contribute(inFolder string, outFolder string, name string) (*Contribution, error) {
+
+	if len(name) > MaxNameLength {
+		return nil, fmt.Errorf("ceremony: name is longer than %d bytes", MaxNameLength)
+	}
+	t, err := LoadTranscript(inFolder)
+	if err != nil {
+		return nil, err
+	}
+	ck, kzg1, kzg2, digest, err := loadKeys(inFolder, t.MN)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(digest, t.Head()) {
+		return nil, fmt.Errorf("ceremony: %s: keys do not match the last contribution of the transcript", inFolder)
+	}
+
+	var x, y mcl.Fr
+	for x.IsZero() || x.IsOne() {
+		x.Random()
+	}
+	for y.IsZero() || y.IsOne() {
+		y.Random()
+	}
+
+	c := Contribution{Name: name, PrevDigest: digest}
+	c.Alpha = newUpdateProof(&x, t, &c, "alpha")
+	c.Beta = newUpdateProof(&y, t, &c, "beta")
+
+	scaleG1Powers(kzg1.PK, x)
+	mcl.G2Mul(&kzg1.VK[1], &kzg1.VK[1], &x)
+	scaleG2Powers(kzg2.PK, y)
+	mcl.G1Mul(&kzg2.VK[1], &kzg2.VK[1], &y)
+	x.Clear()
+	y.Clear()
+
+	for i := uint64(0); i < ck.M; i++ {
+		ck.W[i] = kzg1.PK[2*i]
+		ck.V[i] = kzg2.PK[2*i]
+	}
+	c.AlphaG1 = kzg1.PK[1]
+	c.BetaG2 = kzg2.PK[1]
+
+	c.Digest, err = cm.SaveKeyContainerDigest(outFolder, &ck, &kzg1, &kzg2)
+	if err != nil {
+		return nil, err
+	}
+	t.Contributions = append(t.Contributions, c)
+	if err := SaveTranscript(outFolder, t); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Verify checks the ceremony in folderPath:
+// - the initial keys are the ones of Init
+// - every contribution extends the previous one and proves knowledge of its multipliers
+// - the final keys are the ones the last contribution produced and have the structure of cm.IPPSetupKZG
+// It returns the verified transcript.
+func Verify(folderPath string) (*Transcript, error) {
+
+	t, err := LoadTranscript(folderPath)
+	if err != nil {
+		return nil, err
+	}
+	g, h := Generators()
+	if !t.G.IsEqual(&g) || !t.H.IsEqual(&h) {
+		return nil, fmt.Errorf("ceremony: transcript does not use the ceremony generators")
+	}
+	ck, kzg1, kzg2 := initialKeys(t.MN, g, h)
+	initial, err := cm.WriteKeyContainer(ioutil.Discard, ck, kzg1, kzg2)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(initial, t.InitialDigest) {
+		return nil, fmt.Errorf("ceremony: transcript does not start from the initial keys")
+	}
+
+	prevDigest := t.InitialDigest
+	prevAlpha, prevBeta := g, h
+	for i := range t.Contributions {
+		c := &t.Contributions[i]
+		fail := func(reason string) error {
+			return &ContributionError{i, c.Name, reason}
+		}
+		if !bytes.Equal(c.PrevDigest, prevDigest) {
+			return nil, fail("does not extend the previous keys")
+		}
+		if !c.Alpha.verify(t, c, "alpha") {
+			return nil, fail("invalid proof for the alpha multiplier")
+		}
+		if !c.Beta.verify(t, c, "beta") {
+			return nil, fail("invalid proof for the beta multiplier")
+		}
+		// alpha_i = alpha_{i-1} * x: e(g^{alpha_i}, h) == e(g^{alpha_{i-1}}, h^x)
+		if !pairingEqual(&c.AlphaG1, &h, &prevAlpha, &c.Alpha.G2) {
+			return nil, fail("alpha was not updated by the proven multiplier")
+		}
+		// beta_i = beta_{i-1} * y: e(g, h^{beta_i}) == e(g^y, h^{beta_{i-1}})
+		if !pairingEqual(&g, &c.BetaG2, &c.Beta.G1, &prevBeta) {
+			return nil, fail("beta was not updated by the proven multiplier")
+		}
+		prevDigest, prevAlpha, prevBeta = c.Digest, c.AlphaG1, c.BetaG2
+	}
+
+	ckFinal, kzg1Final, kzg2Final, digest, err := loadKeys(folderPath, t.MN)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(digest, prevDigest) {
+		return nil, fmt.Errorf("ceremony: %s: keys do not match the last contribution of the transcript", folderPath)
+	}
+	if !kzg1Final.PK[0].IsEqual(&g) || !kzg2Final.PK[0].IsEqual(&h) {
+		return nil, fmt.Errorf("ceremony: final keys do not use the ceremony generators")
+	}
+	if !kzg1Final.PK[1].IsEqual(&prevAlpha) || !kzg2Final.PK[1].IsEqual(&prevBeta) {
+		return nil, fmt.Errorf("ceremony: final keys do not match the last contribution")
+	}
+	if err := cm.ValidateKeys(&ckFinal, &kzg1Final, &kzg2Final); err != nil {
+		return nil, fmt.Errorf("ceremony: final keys: %w", err)
+	}
+	return t, nil
+}
+
+// loadKeys reads exactly mn commitment keys and 2mn-1 KZG keys and returns the digest of the container.
+func loadKeys(folderPath string, mn uint64) (cm.Ck, kzg.KZG1Settings, kzg.KZG2Settings, []byte, error) {
+
+	fail := func(err error) (cm.Ck, kzg.KZG1Settings, kzg.KZG2Settings, []byte, error) {
+		return cm.Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, nil, fmt.Errorf("ceremony: %s: %w", folderPath, err)
+	}
+
+	keyFile, err := cm.OpenKeyFile(folderPath)
+	if err != nil {
+		return fail(err)
+	}
+	defer keyFile.Close()
+	if keyFile.CkSize != mn || keyFile.KzgSize != 2*mn-1 {
+		return fail(fmt.Errorf("key sizes are %d and %d, transcript is for %d", keyFile.CkSize, keyFile.KzgSize, mn))
+	}
+	if err := keyFile.VerifyDigest(); err != nil {
+		return fail(err)
+	}
+	digest, err := keyFile.Digest()
+	if err != nil {
+		return fail(err)
+	}
+	ck, err := keyFile.Ck(mn)
+	if err != nil {
+		return fail(err)
+	}
+	kzg1, kzg2, err := keyFile.KZG(mn)
+	if err != nil {
+		return fail(err)
+	}
+	return ck, kzg1, kzg2, digest, nil
+}
+
+// newUpdateProof computes g^x, h^x and the proof of knowledge of x.
+func newUpdateProof(x *mcl.Fr, t *Transcript, c *Contribution, label string) UpdateProof {
+	var proof UpdateProof
+	var k, e mcl.Fr
+	mcl.G1Mul(&proof.G1, &t.G, x)
+	mcl.G2Mul(&proof.G2, &t.H, x)
+
+	k.Random()
+	mcl.G1Mul(&proof.R, &t.G, &k)
+	e = proof.challenge(t, c, label)
+	// Z = k + e * x
+	mcl.FrMul(&e, &e, x)
+	mcl.FrAdd(&proof.Z, &k, &e)
+	k.Clear()
+	return proof
+}
+
+// challenge binds the proof to the keys which are updated and to the name of the contributor.
+func (self *UpdateProof) challenge(t *Transcript, c *Contribution, label string) mcl.Fr {
+	var e mcl.Fr
+	data := make([]byte, 0)
+	data = append(data, []byte("gipa-go ceremony "+label)...)
+	data = append(data, c.PrevDigest...)
+	data = append(data, []byte(c.Name)...)
+	data = append(data, t.G.Serialize()...)
+	data = append(data, self.G1.Serialize()...)
+	data = append(data, self.R.Serialize()...)
+	hash := blake2b.Sum256(data)
+	e.SetHashOf(hash[:])
+	return e
+}
+
+// verify checks the proof of knowledge and that G1 and G2 have the same exponent.
+func (self *UpdateProof) verify(t *Transcript, c *Contribution, label string) bool {
+	if self.G1.IsZero() {
+		return false
+	}
+	// g^Z == R * G1^e
+	var lhs, rhs mcl.G1
+	e := self.challenge(t, c, label)
+	mcl.G1Mul(&lhs, &t.G, &self.Z)
+	mcl.G1Mul(&rhs, &self.G1, &e)
+	mcl.G1Add(&rhs, &rhs, &self.R)
+	if !lhs.IsEqual(&rhs) {
+		return false
+	}
+	// e(G1, h) == e(g, G2)
+	return pairingEqual(&self.G1, &t.H, &t.G, &self.G2)
+}
+
+// pairingEqual checks e(a1, b1) == e(a2, b2) with one multi-pairing.
+func pairingEqual(a1 *mcl.G1, b1 *mcl.G2, a2 *mcl.G1, b2 *mcl.G2) bool {
+	var e mcl.GT
+	P := []mcl.G1{*a1, *a2}
+	Q := []mcl.G2{*b1, *b2}
+	mcl.G1Neg(&P[1], &P[1])
+	mcl.MillerLoopVec(&e, P, Q)
+	mcl.FinalExp(&e, &e)
+	return e.IsOne()
+}
+
+// scaleG1Powers sets PK[i] = PK[i]^{x^i}. The workload is split across the cores.
+func scaleG1Powers(PK []mcl.G1, x mcl.Fr) {
+	parallelChunks(len(PK), func(start int, stop int) {
+		e := utils.FrPow(x, int64(start))
+		for i := start; i < stop; i++ {
+			mcl.G1Mul(&PK[i], &PK[i], &e)
+			mcl.FrMul(&e, &e, &x)
+		}
+		e.Clear()
+	})
+}
+
+// scaleG2Powers sets PK[i] = PK[i]^{x^i}. The workload is split across the cores.
+func scaleG2Powers(PK []mcl.G2, x mcl.Fr) {
+	parallelChunks(len(PK), func(start int, stop int) {
+		e := utils.FrPow(x, int64(start))
+		for i := start; i < stop; i++ {
+			mcl.G2Mul(&PK[i], &PK[i], &e)
+			mcl.FrMul(&e, &e, &x)
+		}
+		e.Clear()
+	})
+}
+
+func parallelChunks(n int, f func(start int, stop int)) {
+	workers := runtime.GOMAXPROCS(0)
+	step := (n + workers - 1) / workers
+	if step < 1 {
+		step = 1
+	}
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += step {
+		stop := start + step
+		if stop > n {
+			stop = n
+		}
+		wg.Add(1)
+		go func(start int, stop int) {
+			defer wg.Done()
+			f(start, stop)
+		}(start, stop)
+	}
+	wg.Wait()
+}
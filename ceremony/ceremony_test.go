@@ -0,0 +1,101 @@
+package ceremony
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+)
+
+func TestCeremony(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	MN := uint64(1) << 3
+	folders := []string{t.TempDir(), t.TempDir(), t.TempDir(), t.TempDir()}
+	if err := Init(folders[0], MN); err != nil {
+		t.Fatalf("Ceremony: Init failed: %v", err)
+	}
+	if _, err := Verify(folders[0]); err != nil {
+		t.Fatalf("Ceremony: Initial keys do not verify: %v", err)
+	}
+	names := []string{"alice", "bob", "carol"}
+	for i, name := range names {
+		if _, err := Contribute(folders[i], folders[i+1], name); err != nil {
+			t.Fatalf("Ceremony: Contribution %d failed: %v", i, err)
+		}
+	}
+
+	t.Run(fmt.Sprintf("%d/Verify;", MN), func(t *testing.T) {
+		transcript, err := Verify(folders[3])
+		if err != nil {
+			t.Fatalf("Ceremony: Verify failed: %v", err)
+		}
+		if len(transcript.Contributions) != len(names) {
+			t.Fatalf("Ceremony: Expected %d contributions, got %d", len(names), len(transcript.Contributions))
+		}
+		for i := range names {
+			if transcript.Contributions[i].Name != names[i] {
+				t.Errorf("Ceremony: Contribution %d is from %q", i, transcript.Contributions[i].Name)
+			}
+		}
+		// The keys are usable as is
+		if _, _, _, err := cm.LoadCkKzg(folders[3], MN); err != nil {
+			t.Errorf("Ceremony: Final keys do not load: %v", err)
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/Reject;", MN), func(t *testing.T) {
+		transcript, _ := LoadTranscript(folders[3])
+		var cerr *ContributionError
+
+		// Renaming a contribution invalidates its proofs
+		tampered := *transcript
+		tampered.Contributions = append([]Contribution{}, transcript.Contributions...)
+		tampered.Contributions[1].Name = "mallory"
+		folder := t.TempDir()
+		copyKeys(t, folders[3], folder)
+		SaveTranscript(folder, &tampered)
+		if _, err := Verify(folder); !errors.As(err, &cerr) || cerr.Index != 1 {
+			t.Errorf("Ceremony: Renamed contribution not detected: %v", err)
+		}
+
+		// A contribution which skips the update of alpha
+		tampered.Contributions = append([]Contribution{}, transcript.Contributions...)
+		tampered.Contributions[2].AlphaG1 = tampered.Contributions[1].AlphaG1
+		SaveTranscript(folder, &tampered)
+		if _, err := Verify(folder); !errors.As(err, &cerr) || cerr.Index != 2 {
+			t.Errorf("Ceremony: Missing update not detected: %v", err)
+		}
+
+		// Dropping a contribution breaks the chain of digests
+		tampered.Contributions = []Contribution{transcript.Contributions[0], transcript.Contributions[2]}
+		SaveTranscript(folder, &tampered)
+		if _, err := Verify(folder); !errors.As(err, &cerr) || cerr.Index != 1 {
+			t.Errorf("Ceremony: Dropped contribution not detected: %v", err)
+		}
+
+		// Keys from a fork of the ceremony
+		fork := t.TempDir()
+		if _, err := Contribute(folders[2], fork, "carol"); err != nil {
+			t.Fatalf("Ceremony: Contribution failed: %v", err)
+		}
+		copyKeys(t, fork, folder)
+		SaveTranscript(folder, transcript)
+		if _, err := Verify(folder); err == nil {
+			t.Errorf("Ceremony: Keys from a fork not detected")
+		}
+	})
+}
+
+func copyKeys(t *testing.T, from string, to string) {
+	data, err := ioutil.ReadFile(from + "/" + cm.KeyContainerFile)
+	if err == nil {
+		err = ioutil.WriteFile(to+"/"+cm.KeyContainerFile, data, 0644)
+	}
+	if err != nil {
+		t.Fatalf("Ceremony: Copying the keys failed: %v", err)
+	}
+}
@@ -0,0 +1,397 @@
+package cm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Version 2 of the key files. Everything is held in a single file, folderPath/KEYS.data.
+// Integers are little endian. Group elements use the compressed mcl serialization.
+//
+// Header (16 bytes):
+// magic "GIPAKEYS" (8) | version (2) | curve id (1) | encoding mode (1) | flags (1) | reserved (3)
+// Generators:
+// G (G1) | H (G2)
+// Section sizes:
+// CkSize (8) | KzgSize (8)
+// Sections, each one stored contiguously (unlike v1, nothing is interleaved):
+// ck.W[0..CkSize) (G1) | ck.V[0..CkSize) (G2)
+// kzg1.VK[0..2) (G2) | kzg2.VK[0..2) (G1)      only if KzgSize > 0
+// kzg1.PK[0..KzgSize) (G1) | kzg2.PK[0..KzgSize) (G2)
+// Trailer:
+// BLAKE2b-256 digest of all the preceding bytes (32)
+//
+// Version 1 is the pair CK.data and KZG.data written by IPPSave and IPPSaveCmKzg.
+// CK.data: M (8) followed by M pairs of (ck.W[i], ck.V[i]).
+// KZG.data: degree (8), then (kzg1.VK[i], kzg2.VK[i]) for i < 2, then degree pairs of (kzg1.PK[i], kzg2.PK[i]).
+
+const (
+	KeyContainerFile    = "KEYS.data"
+	KeyContainerVersion = 2
+	KeyHeaderSize       = 16
+	// Group elements are stored with mcl Serialize, i.e., compressed.
+	KeyEncodingCompressed = 1
+	// Set when ck.W[i] = kzg1.PK[2i] and ck.V[i] = kzg2.PK[2i], as produced by IPPSetupKZG.
+	KeyFlagEvenPowers = 1
+	// Upper bound on the section sizes. Keeps the offset computation far away from overflows.
+	maxKeySectionSize = uint64(1) << 40
+)
+
+var KeyContainerMagic = [8]byte{'G', 'I', 'P', 'A', 'K', 'E', 'Y', 'S'}
+
+var ErrKeyDigest = errors.New("key container: digest mismatch")
+
+// KeyContainerHeader holds everything that precedes the sections in a v2 key file.
+type KeyContainerHeader struct {
+	Version  uint16
+	Curve    uint8
+	Encoding uint8
+	Flags    uint8
+	G        mcl.G1
+	H        mcl.G2
+	CkSize   uint64
+	KzgSize  uint64
+}
+
+// Size of the header, generators and the section sizes.
+func keyPreambleSize() int64 {
+	return int64(KeyHeaderSize + utils.GetG1ByteSize() + utils.GetG2ByteSize() + 16)
+}
+
+// SaveKeyContainer writes ck and, if kzg1 and kzg2 are not nil, the KZG keys to folderPath/KEYS.data.
+func SaveKeyContainer(folderPath string, ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) error {
+	_, err := SaveKeyContainerDigest(folderPath, ck, kzg1, kzg2)
+	return err
+}
+
+// SaveKeyContainerDigest is SaveKeyContainer, but also returns the digest written at the end of the file.
+func SaveKeyContainerDigest(folderPath string, ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) ([]byte, error) {
+
+	if err := os.MkdirAll(folderPath, os.ModePerm); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(folderPath + "/" + KeyContainerFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	digest, err := WriteKeyContainer(bw, ck, kzg1, kzg2)
+	if err != nil {
+		return nil, err
+	}
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+	return digest, f.Close()
+}
+
+// WriteKeyContainer writes the v2 key container to w and returns its digest.
+// kzg1 and kzg2 are optional, but both have to be given together.
+func WriteKeyContainer(out io.Writer, ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) ([]byte, error) {
+
+	if (kzg1 == nil) != (kzg2 == nil) {
+		return nil, fmt.Errorf("key container: both KZG settings are needed")
+	}
+	if ck.M < 1 || uint64(len(ck.W)) != ck.M || uint64(len(ck.V)) != ck.M {
+		return nil, fmt.Errorf("key container: invalid ck size: %d %d %d", ck.M, len(ck.W), len(ck.V))
+	}
+
+	header := KeyContainerHeader{
+		Version:  KeyContainerVersion,
+		Curve:    utils.CurveBLS12_381,
+		Encoding: KeyEncodingCompressed,
+		G:        ck.W[0],
+		H:        ck.V[0],
+		CkSize:   ck.M,
+	}
+	if kzg1 != nil {
+		if len(kzg1.PK) != len(kzg2.PK) || len(kzg1.VK) != 2 || len(kzg2.VK) != 2 {
+			return nil, fmt.Errorf("key container: KZG size mismatch: %d %d", len(kzg1.PK), len(kzg2.PK))
+		}
+		header.KzgSize = uint64(len(kzg1.PK))
+		if IsEvenPowers(ck, kzg1, kzg2) {
+			header.Flags |= KeyFlagEvenPowers
+		}
+	}
+
+	hasher, _ := blake2b.New256(nil)
+	w := io.MultiWriter(out, hasher)
+
+	if err := writeKeyHeader(w, &header); err != nil {
+		return nil, err
+	}
+	for i := range ck.W {
+		if err := utils.WriteG1(w, &ck.W[i]); err != nil {
+			return nil, err
+		}
+	}
+	for i := range ck.V {
+		if err := utils.WriteG2(w, &ck.V[i]); err != nil {
+			return nil, err
+		}
+	}
+	if kzg1 != nil {
+		for i := range kzg1.VK {
+			if err := utils.WriteG2(w, &kzg1.VK[i]); err != nil {
+				return nil, err
+			}
+		}
+		for i := range kzg2.VK {
+			if err := utils.WriteG1(w, &kzg2.VK[i]); err != nil {
+				return nil, err
+			}
+		}
+		for i := range kzg1.PK {
+			if err := utils.WriteG1(w, &kzg1.PK[i]); err != nil {
+				return nil, err
+			}
+		}
+		for i := range kzg2.PK {
+			if err := utils.WriteG2(w, &kzg2.PK[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	digest := hasher.Sum(nil)
+	if _, err := out.Write(digest); err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// IsEvenPowers checks if ck is the even-index subset of the KZG PKs.
+func IsEvenPowers(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) bool {
+	if uint64(len(kzg1.PK)) < 2*ck.M-1 || uint64(len(kzg2.PK)) < 2*ck.M-1 {
+		return false
+	}
+	for i := uint64(0); i < ck.M; i++ {
+		if !ck.W[i].IsEqual(&kzg1.PK[2*i]) || !ck.V[i].IsEqual(&kzg2.PK[2*i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeKeyHeader(w io.Writer, header *KeyContainerHeader) error {
+	data := make([]byte, KeyHeaderSize)
+	copy(data, KeyContainerMagic[:])
+	binary.LittleEndian.PutUint16(data[8:], header.Version)
+	data[10] = header.Curve
+	data[11] = header.Encoding
+	data[12] = header.Flags
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := utils.WriteG1(w, &header.G); err != nil {
+		return err
+	}
+	if err := utils.WriteG2(w, &header.H); err != nil {
+		return err
+	}
+	sizes := make([]byte, 16)
+	binary.LittleEndian.PutUint64(sizes, header.CkSize)
+	binary.LittleEndian.PutUint64(sizes[8:], header.KzgSize)
+	_, err := w.Write(sizes)
+	return err
+}
+
+// ReadKeyHeader reads and validates the header of a v2 key file.
+func ReadKeyHeader(r io.Reader) (KeyContainerHeader, error) {
+
+	var header KeyContainerHeader
+	data := make([]byte, KeyHeaderSize)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return header, fmt.Errorf("key container: reading header: %w", err)
+	}
+	if !bytes.Equal(data[:8], KeyContainerMagic[:]) {
+		return header, fmt.Errorf("key container: bad magic %x", data[:8])
+	}
+	header.Version = binary.LittleEndian.Uint16(data[8:])
+	header.Curve = data[10]
+	header.Encoding = data[11]
+	header.Flags = data[12]
+	if header.Version != KeyContainerVersion {
+		return header, fmt.Errorf("key container: unsupported version %d", header.Version)
+	}
+	if header.Curve != utils.CurveBLS12_381 {
+		return header, fmt.Errorf("key container: unsupported curve id %d", header.Curve)
+	}
+	if header.Encoding != KeyEncodingCompressed {
+		return header, fmt.Errorf("key container: unsupported encoding mode %d", header.Encoding)
+	}
+	if err := utils.ReadG1(r, &header.G); err != nil {
+		return header, fmt.Errorf("key container: G: %w", err)
+	}
+	if err := utils.ReadG2(r, &header.H); err != nil {
+		return header, fmt.Errorf("key container: H: %w", err)
+	}
+	sizes := make([]byte, 16)
+	if _, err := io.ReadFull(r, sizes); err != nil {
+		return header, fmt.Errorf("key container: reading sizes: %w", err)
+	}
+	header.CkSize = binary.LittleEndian.Uint64(sizes)
+	header.KzgSize = binary.LittleEndian.Uint64(sizes[8:])
+	if header.CkSize < 1 || header.CkSize > maxKeySectionSize || header.KzgSize > maxKeySectionSize {
+		return header, fmt.Errorf("key container: invalid section sizes: %d %d", header.CkSize, header.KzgSize)
+	}
+	return header, nil
+}
+
+// FileSize returns the expected size of a v2 key file with this header.
+func (self *KeyContainerHeader) FileSize() int64 {
+	size := keyPreambleSize()
+	size += int64(self.CkSize) * int64(utils.GetG1ByteSize()+utils.GetG2ByteSize())
+	if self.KzgSize > 0 {
+		size += 2 * int64(utils.GetG1ByteSize()+utils.GetG2ByteSize())
+		size += int64(self.KzgSize) * int64(utils.GetG1ByteSize()+utils.GetG2ByteSize())
+	}
+	return size + blake2b.Size256
+}
+
+// loadKeyContainer loads the first M keys from folderPath/KEYS.data.
+// The KZG keys are skipped unless withKzg is set. The whole file is always hashed to check the digest.
+func loadKeyContainer(folderPath string, M uint64, withKzg bool) (Ck, kzg.KZG1Settings, kzg.KZG2Settings, error) {
+
+	var kzg1 kzg.KZG1Settings
+	var kzg2 kzg.KZG2Settings
+	fileName := folderPath + "/" + KeyContainerFile
+	fail := func(err error) (Ck, kzg.KZG1Settings, kzg.KZG2Settings, error) {
+		return Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	if M < 1 || !utils.IsPow2(M) {
+		return fail(fmt.Errorf("requested size %d is not a power of 2", M))
+	}
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return Ck{}, kzg1, kzg2, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	hasher, _ := blake2b.New256(nil)
+	r := io.TeeReader(br, hasher)
+
+	header, err := ReadKeyHeader(r)
+	if err != nil {
+		return fail(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return fail(err)
+	}
+	if info.Size() != header.FileSize() {
+		return fail(fmt.Errorf("file is %d bytes, header implies %d", info.Size(), header.FileSize()))
+	}
+	if M > header.CkSize {
+		return fail(fmt.Errorf("requested %d keys, but file only has %d", M, header.CkSize))
+	}
+	kzgM := 2*M - 1
+	if withKzg && kzgM > header.KzgSize {
+		return fail(fmt.Errorf("requested %d KZG keys, but file only has %d", kzgM, header.KzgSize))
+	}
+
+	ck := Ck{M, make([]mcl.G2, M), make([]mcl.G1, M)}
+	if err := readG1Section(r, ck.W, header.CkSize, "W"); err != nil {
+		return fail(err)
+	}
+	if err := readG2Section(r, ck.V, header.CkSize, "V"); err != nil {
+		return fail(err)
+	}
+	if header.KzgSize > 0 {
+		if withKzg {
+			kzg1 = kzg.KZG1Settings{PK: make([]mcl.G1, kzgM), VK: make([]mcl.G2, 2)}
+			kzg2 = kzg.KZG2Settings{PK: make([]mcl.G2, kzgM), VK: make([]mcl.G1, 2)}
+		}
+		if err := readG2Section(r, kzg1.VK, 2, "KZG1 VK"); err != nil {
+			return fail(err)
+		}
+		if err := readG1Section(r, kzg2.VK, 2, "KZG2 VK"); err != nil {
+			return fail(err)
+		}
+		if err := readG1Section(r, kzg1.PK, header.KzgSize, "KZG1 PK"); err != nil {
+			return fail(err)
+		}
+		if err := readG2Section(r, kzg2.PK, header.KzgSize, "KZG2 PK"); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := checkKeyDigest(br, hasher); err != nil {
+		return fail(err)
+	}
+	if !ck.W[0].IsEqual(&header.G) || !ck.V[0].IsEqual(&header.H) {
+		return fail(fmt.Errorf("generators in the header do not match ck"))
+	}
+	return ck, kzg1, kzg2, nil
+}
+
+// Reads the trailing digest (which is not part of the hash) and makes sure nothing follows it.
+func checkKeyDigest(r io.Reader, hasher hash.Hash) error {
+	digest := make([]byte, blake2b.Size256)
+	if _, err := io.ReadFull(r, digest); err != nil {
+		return fmt.Errorf("reading digest: %w", err)
+	}
+	if !bytes.Equal(digest, hasher.Sum(nil)) {
+		return ErrKeyDigest
+	}
+	if n, _ := io.Copy(ioutil.Discard, r); n != 0 {
+		return fmt.Errorf("%d trailing bytes after the digest", n)
+	}
+	return nil
+}
+
+// Deserializes the section into out and skips the remaining size - len(out) elements.
+func readG1Section(r io.Reader, out []mcl.G1, size uint64, name string) error {
+	data := make([]byte, utils.GetG1ByteSize())
+	for i := range out {
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("reading %s[%d]: %w", name, i, err)
+		}
+		if err := out[i].Deserialize(data); err != nil {
+			return fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+	}
+	return skipSection(r, (size-uint64(len(out)))*uint64(len(data)), name)
+}
+
+func readG2Section(r io.Reader, out []mcl.G2, size uint64, name string) error {
+	data := make([]byte, utils.GetG2ByteSize())
+	for i := range out {
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("reading %s[%d]: %w", name, i, err)
+		}
+		if err := out[i].Deserialize(data); err != nil {
+			return fmt.Errorf("%s[%d]: %w", name, i, err)
+		}
+	}
+	return skipSection(r, (size-uint64(len(out)))*uint64(len(data)), name)
+}
+
+func skipSection(r io.Reader, n uint64, name string) error {
+	if _, err := io.CopyN(ioutil.Discard, r, int64(n)); err != nil {
+		return fmt.Errorf("skipping %s: %w", name, err)
+	}
+	return nil
+}
+
+// hasKeyContainer reports if folderPath holds a v2 key file.
+func hasKeyContainer(folderPath string) bool {
+	_, err := os.Stat(folderPath + "/" + KeyContainerFile)
+	return err == nil
+}
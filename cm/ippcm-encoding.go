@@ -0,0 +1,28 @@
+package cm
+
+import (
+	"io"
+
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// WriteCom writes the three GT elements of com.
+// Size is 3 * utils.GetGTByteSize() bytes.
+func WriteCom(w io.Writer, com *Com) error {
+	for i := range com.Com {
+		if err := utils.WriteGT(w, &com.Com[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadCom reads a commitment written by WriteCom.
+func ReadCom(r io.Reader, com *Com) error {
+	for i := range com.Com {
+		if err := utils.ReadGT(r, &com.Com[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package cm
+
+import (
+	"github.com/alinush/go-mcl"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Hiding AFGHO16 commitments for the zero-knowledge mode of gipa and gipakzg.
+// Each component is blinded with a power of HidingBase:
+//   com = (<A, V> u^{rho_0}, <W, B> u^{rho_1}, Z u^{rho_2})
+// u is a pairing of two hashed points, thus nobody knows its discrete log wrt. the commitment keys.
+// Opening is a sigma protocol for a commitment of size 1: knowledge of A, B and rho with
+//   com = (e(A, V) u^{rho_0}, e(W, B) u^{rho_1}, e(A, B) u^{rho_2})
+// which reveals neither A nor B.
+
+// HidingBase returns u = e(HashAndMapTo("gipa-go hiding G1"), HashAndMapTo("gipa-go hiding G2")).
+func HidingBase() mcl.GT {
+	var g mcl.G1
+	var h mcl.G2
+	var u mcl.GT
+	g.HashAndMapTo([]byte("gipa-go hiding G1"))
+	h.HashAndMapTo([]byte("gipa-go hiding G2"))
+	mcl.Pairing(&u, &g, &h)
+	return u
+}
+
+// ComBlind returns (com[0] u^{rho_0}, com[1] u^{rho_1}, com[2] u^{rho_2}).
+func ComBlind(com *Com, u *mcl.GT, rho [3]mcl.Fr) Com {
+	result := Com{}
+	var temp mcl.GT
+	for i := range com.Com {
+		mcl.GTPow(&temp, u, &rho[i])
+		mcl.GTMul(&result.Com[i], &com.Com[i], &temp)
+	}
+	return result
+}
+
+// HidingTranscript returns Hash(label || com), the initial transcript of a zero-knowledge mode.
+// The commitment is the statement, the label separates the modes of gipa and gipakzg.
+func HidingTranscript(label string, com *Com) [32]byte {
+	data := []byte(label)
+	for i := range com.Com {
+		data = append(data, com.Com[i].Serialize()...)
+	}
+	return blake2b.Sum256(data)
+}
+
+// Blinder holds the blinding factors of a zero-knowledge prover.
+// Each round blinds ComL and ComR with fresh factors, which fold along with the commitment
+// as rho' = x rho_L + rho + x^{-1} rho_R. Rho is then the blinding of the folded commitment, see ProveOpening.
+type Blinder struct {
+	U    mcl.GT // HidingBase
+	Rho  [3]mcl.Fr
+	RhoL [3]mcl.Fr
+	RhoR [3]mcl.Fr
+}
+
+// NewBlinder starts with the blinding factors rho of the statement.
+func NewBlinder(rho [3]mcl.Fr) Blinder {
+	return Blinder{U: HidingBase(), Rho: rho}
+}
+
+// Blind is a member function of Blinder
+// It draws RhoL, RhoR and returns the blinded ComL, ComR.
+func (self *Blinder) Blind(ComL *Com, ComR *Com) (Com, Com) {
+	self.RhoL = RandomBlinding()
+	self.RhoR = RandomBlinding()
+	return ComBlind(ComL, &self.U, self.RhoL), ComBlind(ComR, &self.U, self.RhoR)
+}
+
+// Fold is a member function of Blinder
+// It computes Rho = x RhoL + Rho + x^{-1} RhoR.
+func (self *Blinder) Fold(x mcl.Fr) {
+	var y, temp mcl.Fr
+	mcl.FrInv(&y, &x)
+	for i := range self.Rho {
+		mcl.FrMul(&temp, &x, &self.RhoL[i])
+		mcl.FrAdd(&self.Rho[i], &self.Rho[i], &temp)
+		mcl.FrMul(&temp, &y, &self.RhoR[i])
+		mcl.FrAdd(&self.Rho[i], &self.Rho[i], &temp)
+	}
+}
+
+// IPPCMHiding is IPPCM blinded with u^{rho}.
+func IPPCMHiding(ck *Ck, A []mcl.G1, B []mcl.G2, Z mcl.GT, u *mcl.GT, rho [3]mcl.Fr) Com {
+	com := IPPCM(ck, A, B, Z)
+	return ComBlind(&com, u, rho)
+}
+
+// RandomBlinding returns three random blinding factors.
+func RandomBlinding() [3]mcl.Fr {
+	var rho [3]mcl.Fr
+	for i := range rho {
+		rho[i].Random()
+	}
+	return rho
+}
+
+// Opening is a zero-knowledge opening of a hiding commitment of size 1.
+type Opening struct {
+	R   [4]mcl.GT // e(dA, V) u^{s_0}, e(W, dB) u^{s_1}, e(dA, B) e(A, dB) u^{s_2}, e(dA, dB) u^{s_3}
+	A   mcl.G1    // c A + dA
+	B   mcl.G2    // c B + dB
+	Tau [3]mcl.Fr // c rho_0 + s_0, c rho_1 + s_1, c^2 rho_2 + c s_2 + s_3
+}
+
+// OpeningChallenge returns c = Hash(transcript || R) and updates the transcript.
+func OpeningChallenge(transcript *[32]byte, opening *Opening) mcl.Fr {
+	var c mcl.Fr
+	data := make([]byte, 0)
+	data = append(data, transcript[:]...)
+	for i := range opening.R {
+		data = append(data, opening.R[i].Serialize()...)
+	}
+	hash := blake2b.Sum256(data)
+	copy(transcript[:], hash[:])
+	c.SetHashOf(hash[:])
+	return c
+}
+
+// ProveOpening opens com = IPPCMHiding(ck, [A], [B], e(A, B), u, rho) for a ck of size 1.
+// The challenge is OpeningChallenge(transcript, opening).
+func ProveOpening(ck *Ck, A mcl.G1, B mcl.G2, u *mcl.GT, rho [3]mcl.Fr, transcript *[32]byte) Opening {
+	var opening Opening
+	var dA mcl.G1
+	var dB mcl.G2
+	var s [4]mcl.Fr
+	dA.Random()
+	dB.Random()
+	for i := range s {
+		s[i].Random()
+	}
+
+	var temp, cross mcl.GT
+	mcl.Pairing(&opening.R[0], &dA, &ck.V[0])
+	mcl.Pairing(&opening.R[1], &ck.W[0], &dB)
+	mcl.Pairing(&opening.R[2], &dA, &B)
+	mcl.Pairing(&cross, &A, &dB)
+	mcl.GTMul(&opening.R[2], &opening.R[2], &cross)
+	mcl.Pairing(&opening.R[3], &dA, &dB)
+	for i := range opening.R {
+		mcl.GTPow(&temp, u, &s[i])
+		mcl.GTMul(&opening.R[i], &opening.R[i], &temp)
+	}
+
+	c := OpeningChallenge(transcript, &opening)
+	mcl.G1Mul(&opening.A, &A, &c)
+	mcl.G1Add(&opening.A, &opening.A, &dA)
+	mcl.G2Mul(&opening.B, &B, &c)
+	mcl.G2Add(&opening.B, &opening.B, &dB)
+
+	var frTemp mcl.Fr
+	for i := 0; i < 2; i++ {
+		mcl.FrMul(&frTemp, &c, &rho[i])
+		mcl.FrAdd(&opening.Tau[i], &frTemp, &s[i])
+	}
+	// (c rho_2 + s_2) c + s_3
+	mcl.FrMul(&frTemp, &c, &rho[2])
+	mcl.FrAdd(&frTemp, &frTemp, &s[2])
+	mcl.FrMul(&frTemp, &frTemp, &c)
+	mcl.FrAdd(&opening.Tau[2], &frTemp, &s[3])
+	return opening
+}
+
+// openingLHS returns (e(A, V) u^{tau_0}, e(W, B) u^{tau_1}, e(A, B) u^{tau_2}) for the responses.
+func openingLHS(ck *Ck, u *mcl.GT, opening *Opening) [3]mcl.GT {
+	var result [3]mcl.GT
+	var temp mcl.GT
+	mcl.Pairing(&result[0], &opening.A, &ck.V[0])
+	mcl.Pairing(&result[1], &ck.W[0], &opening.B)
+	mcl.Pairing(&result[2], &opening.A, &opening.B)
+	for i := range result {
+		mcl.GTPow(&temp, u, &opening.Tau[i])
+		mcl.GTMul(&result[i], &result[i], &temp)
+	}
+	return result
+}
+
+// CheckOpening checks an opening of com for a ck of size 1 and the challenge c:
+//
+//	e(A, V) u^{tau_0} == com[0]^c R[0]
+//	e(W, B) u^{tau_1} == com[1]^c R[1]
+//	e(A, B) u^{tau_2} == com[2]^{c^2} R[2]^c R[3]
+func CheckOpening(ck *Ck, u *mcl.GT, com *Com, opening *Opening, c mcl.Fr) bool {
+	lhs := openingLHS(ck, u, opening)
+
+	var cSqr mcl.Fr
+	mcl.FrSqr(&cSqr, &c)
+	var rhs [3]mcl.GT
+	var temp mcl.GT
+	for i := 0; i < 2; i++ {
+		mcl.GTPow(&rhs[i], &com.Com[i], &c)
+		mcl.GTMul(&rhs[i], &rhs[i], &opening.R[i])
+	}
+	mcl.GTPow(&rhs[2], &com.Com[2], &cSqr)
+	mcl.GTPow(&temp, &opening.R[2], &c)
+	mcl.GTMul(&rhs[2], &rhs[2], &temp)
+	mcl.GTMul(&rhs[2], &rhs[2], &opening.R[3])
+
+	for i := range lhs {
+		if !lhs[i].IsEqual(&rhs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// SimulateOpening returns an opening of com for the challenge c without knowing A, B or rho.
+// It picks the responses and R[2] at random and solves for R[0], R[1] and R[3].
+// Such openings are accepted by CheckOpening, which is the zero-knowledge argument for the final step.
+func SimulateOpening(ck *Ck, u *mcl.GT, com *Com, c mcl.Fr) Opening {
+	var opening Opening
+	opening.A.Random()
+	opening.B.Random()
+	for i := range opening.Tau {
+		opening.Tau[i].Random()
+	}
+	var r mcl.Fr
+	r.Random()
+	mcl.GTPow(&opening.R[2], u, &r)
+
+	lhs := openingLHS(ck, u, &opening)
+	var cSqr mcl.Fr
+	mcl.FrSqr(&cSqr, &c)
+	var temp mcl.GT
+	for i := 0; i < 2; i++ {
+		mcl.GTPow(&temp, &com.Com[i], &c)
+		mcl.GTDiv(&opening.R[i], &lhs[i], &temp)
+	}
+	mcl.GTPow(&temp, &com.Com[2], &cSqr)
+	mcl.GTDiv(&opening.R[3], &lhs[2], &temp)
+	mcl.GTPow(&temp, &opening.R[2], &c)
+	mcl.GTDiv(&opening.R[3], &opening.R[3], &temp)
+	return opening
+}
@@ -0,0 +1,118 @@
+package cm
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+type comJSON struct {
+	Com [3]string `json:"Com"`
+}
+
+type ckJSON struct {
+	M uint64   `json:"M"`
+	V []string `json:"V"`
+	W []string `json:"W"`
+}
+
+// MarshalJSON encodes the commitment as hex strings.
+// Value receiver so that commitments nested in other structs are encoded too.
+func (self Com) MarshalJSON() ([]byte, error) {
+	var out comJSON
+	for i := range self.Com {
+		out.Com[i] = utils.GTToHex(&self.Com[i])
+	}
+	return json.Marshal(out)
+}
+
+func (self *Com) UnmarshalJSON(data []byte) error {
+	var in comJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	com := Com{}
+	for i := range in.Com {
+		if err := utils.GTFromHex(&com.Com[i], in.Com[i]); err != nil {
+			return fmt.Errorf("Com[%d]: %w", i, err)
+		}
+	}
+	*self = com
+	return nil
+}
+
+func (self Ck) MarshalJSON() ([]byte, error) {
+	out := ckJSON{self.M, make([]string, len(self.V)), make([]string, len(self.W))}
+	for i := range self.V {
+		out.V[i] = utils.G2ToHex(&self.V[i])
+	}
+	for i := range self.W {
+		out.W[i] = utils.G1ToHex(&self.W[i])
+	}
+	return json.Marshal(out)
+}
+
+func (self *Ck) UnmarshalJSON(data []byte) error {
+	var in ckJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if !utils.IsPow2(in.M) {
+		return fmt.Errorf("Ck: M is not a power of 2: %d", in.M)
+	}
+	if uint64(len(in.V)) != in.M || uint64(len(in.W)) != in.M {
+		return fmt.Errorf("Ck: size mismatch: M is %d, V has %d, W has %d", in.M, len(in.V), len(in.W))
+	}
+	ck := Ck{}
+	ck.New(in.M)
+	for i := range in.V {
+		if err := utils.G2FromHex(&ck.V[i], in.V[i]); err != nil {
+			return fmt.Errorf("Ck: V[%d]: %w", i, err)
+		}
+	}
+	for i := range in.W {
+		if err := utils.G1FromHex(&ck.W[i], in.W[i]); err != nil {
+			return fmt.Errorf("Ck: W[%d]: %w", i, err)
+		}
+	}
+	*self = ck
+	return nil
+}
+
+// ComRoundJSON holds the left and right commitments of one GIPA round.
+// Proofs are encoded with one entry per round so that tools can pinpoint the round which differs.
+type ComRoundJSON struct {
+	Round int `json:"Round"`
+	L     Com `json:"L"`
+	R     Com `json:"R"`
+}
+
+// RoundsToJSON zips L and R into one entry per round.
+func RoundsToJSON(L []Com, R []Com) ([]ComRoundJSON, error) {
+	if len(L) != len(R) {
+		return nil, fmt.Errorf("L and R size mismatch: %d %d", len(L), len(R))
+	}
+	rounds := make([]ComRoundJSON, len(L))
+	for i := range L {
+		rounds[i] = ComRoundJSON{i, L[i], R[i]}
+	}
+	return rounds, nil
+}
+
+// RoundsFromJSON is the inverse of RoundsToJSON. Rounds have to be listed in order.
+func RoundsFromJSON(rounds []ComRoundJSON) ([]Com, []Com, error) {
+	if len(rounds) > utils.MaxRounds {
+		return nil, nil, fmt.Errorf("invalid number of rounds: %d", len(rounds))
+	}
+	L := make([]Com, len(rounds))
+	R := make([]Com, len(rounds))
+	for i := range rounds {
+		if rounds[i].Round != i {
+			return nil, nil, fmt.Errorf("round %d is listed at position %d", rounds[i].Round, i)
+		}
+		L[i] = rounds[i].L
+		R[i] = rounds[i].R
+	}
+	return L, R, nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package cm
+
+import (
+	"io"
+	"os"
+)
+
+// No mmap on this platform. Read the whole file instead.
+func mmapFile(f *os.File, size int64) ([]byte, error) {
+	data := make([]byte, size)
+	_, err := io.ReadFull(f, data)
+	return data, err
+}
+
+func munmapFile(data []byte) error {
+	return nil
+}
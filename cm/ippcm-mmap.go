@@ -0,0 +1,385 @@
+package cm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+	"golang.org/x/crypto/blake2b"
+)
+
+// keySection locates a run of group elements inside a mapped key file.
+// Element i lives at data[offset + i*stride:][:elemSize].
+// In v2 files stride == elemSize. In v1 files the G1 and G2 elements are interleaved.
+type keySection struct {
+	data     []byte
+	offset   int64
+	stride   int64
+	elemSize int64
+	size     uint64
+}
+
+func (self *keySection) at(i uint64) []byte {
+	start := self.offset + int64(i)*self.stride
+	return self.data[start : start+self.elemSize]
+}
+
+func (self *keySection) fits() bool {
+	if self.size == 0 {
+		return true
+	}
+	return self.offset+int64(self.size-1)*self.stride+self.elemSize <= int64(len(self.data))
+}
+
+// KeyFile is a memory-mapped set of keys (either the v2 container or the v1 CK.data/KZG.data pair).
+// Nothing is deserialized when opening. Ranges are deserialized on demand, thus
+// a prover for a small M only touches the prefix it needs.
+// The KeyFile must be closed once all the required keys are loaded.
+type KeyFile struct {
+	Version uint16
+	CkSize  uint64
+	KzgSize uint64
+
+	w, v           keySection
+	kzg1VK, kzg2VK keySection
+	kzg1PK, kzg2PK keySection
+
+	maps [][]byte
+	// Only set for v2 files
+	container []byte
+}
+
+// OpenKeyFile maps the keys in folderPath. The v2 container is preferred, if present.
+func OpenKeyFile(folderPath string) (*KeyFile, error) {
+	self := &KeyFile{}
+	var err error
+	if hasKeyContainer(folderPath) {
+		err = self.openV2(folderPath + "/" + KeyContainerFile)
+	} else {
+		err = self.openV1(folderPath)
+	}
+	if err != nil {
+		self.Close()
+		return nil, err
+	}
+	return self, nil
+}
+
+func (self *KeyFile) mapFile(fileName string) ([]byte, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, err := mmapFile(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	self.maps = append(self.maps, data)
+	return data, nil
+}
+
+func (self *KeyFile) openV2(fileName string) error {
+
+	data, err := self.mapFile(fileName)
+	if err != nil {
+		return err
+	}
+	header, err := ReadKeyHeader(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+	if int64(len(data)) != header.FileSize() {
+		return fmt.Errorf("%s: file is %d bytes, header implies %d", fileName, len(data), header.FileSize())
+	}
+
+	g1, g2 := int64(utils.GetG1ByteSize()), int64(utils.GetG2ByteSize())
+	self.Version = header.Version
+	self.CkSize = header.CkSize
+	self.KzgSize = header.KzgSize
+	self.container = data
+
+	offset := keyPreambleSize()
+	self.w = keySection{data, offset, g1, g1, header.CkSize}
+	offset += int64(header.CkSize) * g1
+	self.v = keySection{data, offset, g2, g2, header.CkSize}
+	offset += int64(header.CkSize) * g2
+	if header.KzgSize > 0 {
+		self.kzg1VK = keySection{data, offset, g2, g2, 2}
+		offset += 2 * g2
+		self.kzg2VK = keySection{data, offset, g1, g1, 2}
+		offset += 2 * g1
+		self.kzg1PK = keySection{data, offset, g1, g1, header.KzgSize}
+		offset += int64(header.KzgSize) * g1
+		self.kzg2PK = keySection{data, offset, g2, g2, header.KzgSize}
+	}
+	return nil
+}
+
+func (self *KeyFile) openV1(folderPath string) error {
+
+	g1, g2 := int64(utils.GetG1ByteSize()), int64(utils.GetG2ByteSize())
+	pair := g1 + g2
+
+	fileName := folderPath + "/CK.data"
+	data, err := self.mapFile(fileName)
+	if err != nil {
+		return err
+	}
+	if len(data) < 8 {
+		return fmt.Errorf("%s: reading header: file is too short", fileName)
+	}
+	self.Version = 1
+	self.CkSize = binary.LittleEndian.Uint64(data)
+	if self.CkSize > maxKeySectionSize {
+		return fmt.Errorf("%s: invalid size %d", fileName, self.CkSize)
+	}
+	self.w = keySection{data, 8, pair, g1, self.CkSize}
+	self.v = keySection{data, 8 + g1, pair, g2, self.CkSize}
+	if !self.w.fits() || !self.v.fits() {
+		return fmt.Errorf("%s: file is truncated, header says %d keys", fileName, self.CkSize)
+	}
+
+	fileName = folderPath + "/KZG.data"
+	if _, err := os.Stat(fileName); err != nil {
+		return nil // CK only
+	}
+	data, err = self.mapFile(fileName)
+	if err != nil {
+		return err
+	}
+	if len(data) < 8 {
+		return fmt.Errorf("%s: reading header: file is too short", fileName)
+	}
+	self.KzgSize = binary.LittleEndian.Uint64(data)
+	if self.KzgSize > maxKeySectionSize {
+		return fmt.Errorf("%s: invalid size %d", fileName, self.KzgSize)
+	}
+	self.kzg1VK = keySection{data, 8, pair, g2, 2}
+	self.kzg2VK = keySection{data, 8 + g2, pair, g1, 2}
+	self.kzg1PK = keySection{data, 8 + 2*pair, pair, g1, self.KzgSize}
+	self.kzg2PK = keySection{data, 8 + 2*pair + g1, pair, g2, self.KzgSize}
+	if !self.kzg1VK.fits() || !self.kzg2VK.fits() || !self.kzg1PK.fits() || !self.kzg2PK.fits() {
+		return fmt.Errorf("%s: file is truncated, header says %d keys", fileName, self.KzgSize)
+	}
+	return nil
+}
+
+// Close unmaps the files. Keys which were already loaded remain valid.
+func (self *KeyFile) Close() error {
+	var err error
+	for _, data := range self.maps {
+		if e := munmapFile(data); e != nil && err == nil {
+			err = e
+		}
+	}
+	self.maps = nil
+	self.container = nil
+	return err
+}
+
+// VerifyDigest checks the BLAKE2b digest of a v2 file. This touches the whole file.
+// v1 files do not have a digest and always fail.
+func (self *KeyFile) VerifyDigest() error {
+	if self.container == nil {
+		return fmt.Errorf("key file: version %d has no digest", self.Version)
+	}
+	n := len(self.container) - blake2b.Size256
+	digest := blake2b.Sum256(self.container[:n])
+	if !bytes.Equal(digest[:], self.container[n:]) {
+		return ErrKeyDigest
+	}
+	return nil
+}
+
+// Digest returns the digest stored at the end of a v2 file. It is not checked, see VerifyDigest.
+func (self *KeyFile) Digest() ([]byte, error) {
+	if self.container == nil {
+		return nil, fmt.Errorf("key file: version %d has no digest", self.Version)
+	}
+	digest := make([]byte, blake2b.Size256)
+	copy(digest, self.container[len(self.container)-blake2b.Size256:])
+	return digest, nil
+}
+
+// Ck deserializes the first M commitment keys.
+func (self *KeyFile) Ck(M uint64) (Ck, error) {
+
+	if M < 1 || !utils.IsPow2(M) {
+		return Ck{}, fmt.Errorf("key file: requested size %d is not a power of 2", M)
+	}
+	if M > self.CkSize {
+		return Ck{}, fmt.Errorf("key file: requested %d keys, but file only has %d", M, self.CkSize)
+	}
+	ck := Ck{M, make([]mcl.G2, M), make([]mcl.G1, M)}
+	if err := self.ReadW(0, ck.W); err != nil {
+		return Ck{}, err
+	}
+	if err := self.ReadV(0, ck.V); err != nil {
+		return Ck{}, err
+	}
+	return ck, nil
+}
+
+// KZG deserializes the VKs and the first 2M-1 KZG PKs.
+func (self *KeyFile) KZG(M uint64) (kzg.KZG1Settings, kzg.KZG2Settings, error) {
+
+	if M < 1 || !utils.IsPow2(M) {
+		return kzg.KZG1Settings{}, kzg.KZG2Settings{}, fmt.Errorf("key file: requested size %d is not a power of 2", M)
+	}
+	kzgM := 2*M - 1
+	if kzgM > self.KzgSize {
+		return kzg.KZG1Settings{}, kzg.KZG2Settings{}, fmt.Errorf("key file: requested %d KZG keys, but file only has %d", kzgM, self.KzgSize)
+	}
+	kzg1 := kzg.KZG1Settings{PK: make([]mcl.G1, kzgM), VK: make([]mcl.G2, 2)}
+	kzg2 := kzg.KZG2Settings{PK: make([]mcl.G2, kzgM), VK: make([]mcl.G1, 2)}
+
+	err := readG2Range(&self.kzg1VK, 0, kzg1.VK, "KZG1 VK")
+	if err == nil {
+		err = readG1Range(&self.kzg2VK, 0, kzg2.VK, "KZG2 VK")
+	}
+	if err == nil {
+		err = self.ReadKZG1PK(0, kzg1.PK)
+	}
+	if err == nil {
+		err = self.ReadKZG2PK(0, kzg2.PK)
+	}
+	if err != nil {
+		return kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
+	return kzg1, kzg2, nil
+}
+
+// ReadW deserializes ck.W[start:start+len(out)] into out.
+func (self *KeyFile) ReadW(start uint64, out []mcl.G1) error {
+	return readG1Range(&self.w, start, out, "W")
+}
+
+// ReadV deserializes ck.V[start:start+len(out)] into out.
+func (self *KeyFile) ReadV(start uint64, out []mcl.G2) error {
+	return readG2Range(&self.v, start, out, "V")
+}
+
+// ReadKZG1PK deserializes kzg1.PK[start:start+len(out)] into out.
+func (self *KeyFile) ReadKZG1PK(start uint64, out []mcl.G1) error {
+	return readG1Range(&self.kzg1PK, start, out, "KZG1 PK")
+}
+
+// ReadKZG2PK deserializes kzg2.PK[start:start+len(out)] into out.
+func (self *KeyFile) ReadKZG2PK(start uint64, out []mcl.G2) error {
+	return readG2Range(&self.kzg2PK, start, out, "KZG2 PK")
+}
+
+func checkRange(sec *keySection, start uint64, n int, name string) error {
+	if sec.data == nil {
+		return fmt.Errorf("key file: %s is not present or the file is closed", name)
+	}
+	if start > sec.size || uint64(n) > sec.size-start {
+		return fmt.Errorf("key file: %s[%d:%d] is out of range, file has %d", name, start, start+uint64(n), sec.size)
+	}
+	return nil
+}
+
+func readG1Range(sec *keySection, start uint64, out []mcl.G1, name string) error {
+	if err := checkRange(sec, start, len(out), name); err != nil {
+		return err
+	}
+	return parallelRange(len(out), func(i int) error {
+		if err := out[i].Deserialize(sec.at(start + uint64(i))); err != nil {
+			return fmt.Errorf("key file: %s[%d]: %w", name, start+uint64(i), err)
+		}
+		return nil
+	})
+}
+
+func readG2Range(sec *keySection, start uint64, out []mcl.G2, name string) error {
+	if err := checkRange(sec, start, len(out), name); err != nil {
+		return err
+	}
+	return parallelRange(len(out), func(i int) error {
+		if err := out[i].Deserialize(sec.at(start + uint64(i))); err != nil {
+			return fmt.Errorf("key file: %s[%d]: %w", name, start+uint64(i), err)
+		}
+		return nil
+	})
+}
+
+// parallelRange calls f for every index in [0, n), splitting the work across GOMAXPROCS goroutines.
+// Each goroutine stops at its first error. The error at the lowest index is returned.
+func parallelRange(n int, f func(i int) error) error {
+
+	workers := runtime.GOMAXPROCS(0)
+	step := (n + workers - 1) / workers
+	if step < 1 {
+		step = 1
+	}
+	errs := make([]error, (n+step-1)/step)
+	var wg sync.WaitGroup
+	for c := range errs {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			stop := utils.MinUint64(uint64((c+1)*step), uint64(n))
+			for i := c * step; i < int(stop); i++ {
+				if err := f(i); err != nil {
+					errs[c] = err
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadKeysMapped maps the key files, deserializes the first M keys in parallel and unmaps them.
+// As in LoadCkKzg, the digest of a v2 file is checked first, which reads the whole file.
+func LoadKeysMapped(folderPath string, M uint64) (Ck, kzg.KZG1Settings, kzg.KZG2Settings, error) {
+	return loadKeysMapped(folderPath, M, true)
+}
+
+// LoadKeysMappedUnverified is LoadKeysMapped without the digest check, thus only the first M keys are read.
+// Use it only for files which are already trusted.
+func LoadKeysMappedUnverified(folderPath string, M uint64) (Ck, kzg.KZG1Settings, kzg.KZG2Settings, error) {
+	return loadKeysMapped(folderPath, M, false)
+}
+
+func loadKeysMapped(folderPath string, M uint64, verify bool) (Ck, kzg.KZG1Settings, kzg.KZG2Settings, error) {
+
+	keyFile, err := OpenKeyFile(folderPath)
+	if err != nil {
+		return Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
+	defer keyFile.Close()
+
+	if verify && keyFile.Version == KeyContainerVersion {
+		if err := keyFile.VerifyDigest(); err != nil {
+			return Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, fmt.Errorf("%s: %w", folderPath, err)
+		}
+	}
+
+	ck, err := keyFile.Ck(M)
+	if err != nil {
+		return Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
+	kzg1, kzg2, err := keyFile.KZG(M)
+	if err != nil {
+		return Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
+	return ck, kzg1, kzg2, nil
+}
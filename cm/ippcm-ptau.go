@@ -0,0 +1,262 @@
+package cm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Import of BLS12-381 powers-of-tau transcripts in the snarkjs .ptau layout.
+// Transcripts from the perpetual powers-of-tau (challenge/response files) can be
+// converted to this layout with "snarkjs powersoftau import response".
+//
+// .ptau layout (little endian):
+// "ptau" (4) | version (4) | number of sections (4)
+// then each section: type (4) | size (8) | data
+// Section 1, header: n8 (4) | q (n8) | power (4) | ...
+// Section 2, tauG1: 2^{power+1} - 1 G1 points
+// Section 3, tauG2: 2^{power} G2 points
+// Points are affine and uncompressed, x then y. Field elements are n8 bytes in Montgomery form.
+// Fp2 elements are c0 then c1.
+
+const (
+	ptauSectionHeader = 1
+	ptauSectionTauG1  = 2
+	ptauSectionTauG2  = 3
+	ptauFieldSize     = 48 // n8 for BLS12-381
+)
+
+type ptauSection struct {
+	offset int64
+	size   int64
+}
+
+// ptauFile holds the positions of the sections. Points are read lazily with ReadAt.
+type ptauFile struct {
+	f        *os.File
+	name     string
+	power    uint32
+	sections map[uint32]ptauSection
+	q        big.Int
+	rInv     big.Int // Inverse of the Montgomery factor 2^{384} mod q
+}
+
+func openPtau(fileName string) (*ptauFile, error) {
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	self := &ptauFile{f: f, name: fileName, sections: make(map[uint32]ptauSection)}
+	if err := self.readSections(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+	return self, nil
+}
+
+func (self *ptauFile) readSections() error {
+
+	data := make([]byte, 12)
+	if _, err := self.f.ReadAt(data, 0); err != nil {
+		return fmt.Errorf("reading header: %w", err)
+	}
+	if string(data[:4]) != "ptau" {
+		return fmt.Errorf("not a ptau file, magic is %x", data[:4])
+	}
+	nSections := binary.LittleEndian.Uint32(data[8:])
+	info, err := self.f.Stat()
+	if err != nil {
+		return err
+	}
+
+	offset := int64(12)
+	for i := uint32(0); i < nSections; i++ {
+		if _, err := self.f.ReadAt(data, offset); err != nil {
+			return fmt.Errorf("reading section %d: %w", i, err)
+		}
+		sectionType := binary.LittleEndian.Uint32(data)
+		size := int64(binary.LittleEndian.Uint64(data[4:]))
+		offset += 12
+		if size < 0 || offset+size > info.Size() {
+			return fmt.Errorf("section %d is truncated", sectionType)
+		}
+		self.sections[sectionType] = ptauSection{offset, size}
+		offset += size
+	}
+
+	header, ok := self.sections[ptauSectionHeader]
+	if !ok || header.size < 4+ptauFieldSize+4 {
+		return fmt.Errorf("missing header section")
+	}
+	data = make([]byte, 4+ptauFieldSize+4)
+	if _, err := self.f.ReadAt(data, header.offset); err != nil {
+		return fmt.Errorf("reading header section: %w", err)
+	}
+	if n8 := binary.LittleEndian.Uint32(data); n8 != ptauFieldSize {
+		return fmt.Errorf("field size is %d bytes, expected %d (BLS12-381)", n8, ptauFieldSize)
+	}
+	self.q.SetBytes(reverseBytes(data[4 : 4+ptauFieldSize]))
+	if self.q.String() != mcl.GetFieldOrder() {
+		return fmt.Errorf("field modulus is not the BLS12-381 one")
+	}
+	self.power = binary.LittleEndian.Uint32(data[4+ptauFieldSize:])
+	if self.power > 40 {
+		return fmt.Errorf("invalid power %d", self.power)
+	}
+
+	var r big.Int
+	r.Lsh(big.NewInt(1), 8*ptauFieldSize)
+	self.rInv.ModInverse(&r, &self.q)
+	return nil
+}
+
+func (self *ptauFile) Close() error {
+	return self.f.Close()
+}
+
+// readSection returns the bytes of count elements of elemSize from the start of the section.
+func (self *ptauFile) readSection(sectionType uint32, count uint64, elemSize int64) ([]byte, error) {
+	section, ok := self.sections[sectionType]
+	if !ok {
+		return nil, fmt.Errorf("%s: missing section %d", self.name, sectionType)
+	}
+	size := int64(count) * elemSize
+	if size > section.size {
+		return nil, fmt.Errorf("%s: section %d has %d points, need %d", self.name, sectionType, section.size/elemSize, count)
+	}
+	data := make([]byte, size)
+	if _, err := self.f.ReadAt(data, section.offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("%s: reading section %d: %w", self.name, sectionType, err)
+	}
+	return data, nil
+}
+
+// fromMontgomery converts the little endian Montgomery form into a decimal string.
+func (self *ptauFile) fromMontgomery(data []byte) string {
+	var x big.Int
+	x.SetBytes(reverseBytes(data))
+	x.Mul(&x, &self.rInv)
+	x.Mod(&x, &self.q)
+	return x.String()
+}
+
+// TauG1 reads the first count powers of tau in G1.
+func (self *ptauFile) TauG1(count uint64) ([]mcl.G1, error) {
+	elemSize := int64(2 * ptauFieldSize)
+	data, err := self.readSection(ptauSectionTauG1, count, elemSize)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]mcl.G1, count)
+	err = parallelRange(int(count), func(i int) error {
+		p := data[int64(i)*elemSize:]
+		s := "1 " + self.fromMontgomery(p[:ptauFieldSize]) + " " + self.fromMontgomery(p[ptauFieldSize:2*ptauFieldSize])
+		if err := out[i].SetString(s, 10); err != nil || !out[i].IsValidOrder() {
+			return fmt.Errorf("%s: tauG1[%d] is not a valid point", self.name, i)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// TauG2 reads the first count powers of tau in G2.
+func (self *ptauFile) TauG2(count uint64) ([]mcl.G2, error) {
+	elemSize := int64(4 * ptauFieldSize)
+	data, err := self.readSection(ptauSectionTauG2, count, elemSize)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]mcl.G2, count)
+	err = parallelRange(int(count), func(i int) error {
+		p := data[int64(i)*elemSize:]
+		s := "1"
+		for j := 0; j < 4; j++ {
+			s += " " + self.fromMontgomery(p[j*ptauFieldSize:(j+1)*ptauFieldSize])
+		}
+		if err := out[i].SetString(s, 10); err != nil || !out[i].IsValidOrder() {
+			return fmt.Errorf("%s: tauG2[%d] is not a valid point", self.name, i)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func reverseBytes(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i := range data {
+		out[len(data)-1-i] = data[i]
+	}
+	return out
+}
+
+// ImportPtau derives ck and the KZG keys for instances of size mn from BLS12-381 .ptau transcripts.
+// GIPA+KZG uses two trapdoors. alpha drives the G1 side (kzg1.PK, ck.W) and beta the G2 side (kzg2.PK, ck.V):
+// - alphaFile provides g^{alpha^i} (tauG1) and h^{alpha} (tauG2[1])
+// - betaFile provides h^{beta^i} (tauG2) and g^{beta} (tauG1[1])
+// Both transcripts must use the same generators. The result is checked with ValidateKeys.
+//
+// The two transcripts must come from independent ceremonies: the AFGHO commitment in IPPCM is no longer
+// binding when alpha = beta, thus ImportPtau rejects transcripts with the same g^{tau}.
+func ImportPtau(alphaFile string, betaFile string, mn uint64) (*Ck, *kzg.KZG1Settings, *kzg.KZG2Settings, error) {
+
+	if mn < 1 || !utils.IsPow2(mn) {
+		return nil, nil, nil, fmt.Errorf("ptau: size %d is not a power of 2", mn)
+	}
+	ubound := 2*mn - 1
+
+	alphaPtau, err := openPtau(alphaFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer alphaPtau.Close()
+	betaPtau, err := openPtau(betaFile)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer betaPtau.Close()
+
+	// kzg1 from the alpha transcript
+	alphaG1, err := alphaPtau.TauG1(ubound)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	alphaG2, err := alphaPtau.TauG2(2)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// kzg2 from the beta transcript
+	betaG2, err := betaPtau.TauG2(ubound)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	betaG1, err := betaPtau.TauG1(2)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	kzg1 := kzg.NewKZG1Settings(alphaG1, []mcl.G2{betaG2[0], alphaG2[1]})
+	kzg2 := kzg.NewKZG2Settings(betaG2, []mcl.G1{alphaG1[0], betaG1[1]})
+	if !alphaG2[0].IsEqual(&betaG2[0]) || !betaG1[0].IsEqual(&alphaG1[0]) {
+		return nil, nil, nil, fmt.Errorf("ptau: %s and %s use different generators", alphaFile, betaFile)
+	}
+	if alphaG1[1].IsEqual(&betaG1[1]) {
+		return nil, nil, nil, fmt.Errorf("ptau: %s and %s use the same trapdoor", alphaFile, betaFile)
+	}
+
+	ck := Ck{mn, make([]mcl.G2, mn), make([]mcl.G1, mn)}
+	for i := uint64(0); i < mn; i++ {
+		ck.W[i] = kzg1.PK[2*i]
+		ck.V[i] = kzg2.PK[2*i]
+	}
+	if err := ValidateKeys(&ck, kzg1, kzg2); err != nil {
+		return nil, nil, nil, fmt.Errorf("ptau: %w", err)
+	}
+	return &ck, kzg1, kzg2, nil
+}
@@ -1,8 +1,10 @@
 package cm
 
 import (
+	"bufio"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/alinush/go-mcl"
@@ -41,37 +43,77 @@ func IPPSave(ck *Ck, folderPath string) {
 // Input: M, folderPath
 // Files are saved in folderPath/CK.data
 // M needs to be a power of 2
+// Panics on error, see LoadCk for the error returning variant.
 func IPPCMLoad(M uint64, folderPath string) Ck {
 
-	fileName := folderPath + "/CK.data"
-	f, err := os.Open(fileName)
+	ck, err := LoadCk(folderPath, M)
 	check(err)
+	return ck
+}
 
-	var m uint64
-	data := make([]byte, 8)
+// LoadCk loads the first M commitment keys from folderPath.
+// If folderPath/KEYS.data (v2) exists it is used, otherwise it falls back to folderPath/CK.data (v1).
+// M needs to be a power of 2 and at most the size recorded in the file header.
+func LoadCk(folderPath string, M uint64) (Ck, error) {
 
-	_, err = f.Read(data)
+	if hasKeyContainer(folderPath) {
+		ck, _, _, err := loadKeyContainer(folderPath, M, false)
+		return ck, err
+	}
 
-	m = binary.LittleEndian.Uint64(data)
+	fileName := folderPath + "/CK.data"
+	f, err := os.Open(fileName)
+	if err != nil {
+		return Ck{}, err
+	}
+	defer f.Close()
 
+	r := bufio.NewReader(f)
+	m, err := readSizeHeader(r, fileName)
+	if err != nil {
+		return Ck{}, err
+	}
+	if M < 1 || !utils.IsPow2(M) {
+		return Ck{}, fmt.Errorf("%s: requested size %d is not a power of 2", fileName, M)
+	}
 	if M > m {
-		panic("CK Load Error: There is not enough to read")
+		return Ck{}, fmt.Errorf("%s: requested %d keys, but file only has %d", fileName, M, m)
 	}
-	ck := Ck{M, make([]mcl.G2, M), make([]mcl.G1, M)}
 
+	ck := Ck{M, make([]mcl.G2, M), make([]mcl.G1, M)}
 	dataG1 := make([]byte, utils.GetG1ByteSize())
 	dataG2 := make([]byte, utils.GetG2ByteSize())
 	for i := uint64(0); i < ck.M; i++ {
-		_, err = f.Read(dataG1)
-		check(err)
-		ck.W[i].Deserialize(dataG1)
-		_, err = f.Read(dataG2)
-		check(err)
-		ck.V[i].Deserialize(dataG2)
+		if err := readElement(r, dataG1, fileName, "W", i); err != nil {
+			return Ck{}, err
+		}
+		if err := ck.W[i].Deserialize(dataG1); err != nil {
+			return Ck{}, fmt.Errorf("%s: W[%d]: %w", fileName, i, err)
+		}
+		if err := readElement(r, dataG2, fileName, "V", i); err != nil {
+			return Ck{}, err
+		}
+		if err := ck.V[i].Deserialize(dataG2); err != nil {
+			return Ck{}, fmt.Errorf("%s: V[%d]: %w", fileName, i, err)
+		}
 	}
+	return ck, nil
+}
 
-	defer f.Close()
-	return ck
+// readSizeHeader reads the little endian uint64 at the start of the key files.
+func readSizeHeader(r io.Reader, fileName string) (uint64, error) {
+	data := make([]byte, 8)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return 0, fmt.Errorf("%s: reading header: %w", fileName, err)
+	}
+	return binary.LittleEndian.Uint64(data), nil
+}
+
+func readElement(r io.Reader, data []byte, fileName string, name string, i uint64) error {
+	if _, err := io.ReadFull(r, data); err != nil {
+		return fmt.Errorf("%s: reading %s[%d]: %w", fileName, name, i, err)
+	}
+	return nil
 }
 
 // Saves the commitment keys to a folder.
@@ -128,23 +170,51 @@ func IPPSaveCmKzg(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, folder
 // Input: M, folderPath
 // Files are saved in folderPath/CK.data
 // M needs to be a power of 2
+// Panics on error, see LoadCkKzg for the error returning variant.
 func IPPCMLoadCmKzg(M uint64, folderPath string) (Ck, kzg.KZG1Settings, kzg.KZG2Settings) {
 
-	ck := IPPCMLoad(M, folderPath)
-
-	fileName := folderPath + "/KZG.data"
-	f, err := os.Open(fileName)
+	ck, kzg1, kzg2, err := LoadCkKzg(folderPath, M)
 	check(err)
+	return ck, kzg1, kzg2
+}
 
-	var m uint64
-	data := make([]byte, 8)
+// LoadCkKzg loads the first M commitment keys and the first 2M-1 KZG keys from folderPath.
+// If folderPath/KEYS.data (v2) exists it is used, otherwise it falls back to
+// folderPath/CK.data and folderPath/KZG.data (v1).
+func LoadCkKzg(folderPath string, M uint64) (Ck, kzg.KZG1Settings, kzg.KZG2Settings, error) {
+
+	if hasKeyContainer(folderPath) {
+		return loadKeyContainer(folderPath, M, true)
+	}
+
+	ck, err := LoadCk(folderPath, M)
+	if err != nil {
+		return Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
+	kzg1, kzg2, err := loadKzg(folderPath, M)
+	if err != nil {
+		return Ck{}, kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
+	return ck, kzg1, kzg2, nil
+}
+
+func loadKzg(folderPath string, M uint64) (kzg.KZG1Settings, kzg.KZG2Settings, error) {
 
-	_, err = f.Read(data)
+	fileName := folderPath + "/KZG.data"
+	f, err := os.Open(fileName)
+	if err != nil {
+		return kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
+	defer f.Close()
 
-	m = binary.LittleEndian.Uint64(data)
+	r := bufio.NewReader(f)
+	m, err := readSizeHeader(r, fileName)
+	if err != nil {
+		return kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
 	kzgM := 2*M - 1
 	if m < kzgM {
-		panic("CK KZG Load Error: There is not enough to read")
+		return kzg.KZG1Settings{}, kzg.KZG2Settings{}, fmt.Errorf("%s: requested %d keys, but file only has %d", fileName, kzgM, m)
 	}
 
 	dataG1 := make([]byte, utils.GetG1ByteSize())
@@ -153,36 +223,48 @@ func IPPCMLoadCmKzg(M uint64, folderPath string) (Ck, kzg.KZG1Settings, kzg.KZG2
 	kzg1 := kzg.KZG1Settings{PK: make([]mcl.G1, kzgM), VK: make([]mcl.G2, 2)}
 	kzg2 := kzg.KZG2Settings{PK: make([]mcl.G2, kzgM), VK: make([]mcl.G1, 2)}
 
-	// Read VK
-	{
-		for i := 0; i < 2; i++ {
-			_, err = f.Read(dataG2)
-			check(err)
-			kzg1.VK[i].Deserialize(dataG2)
+	fail := func(err error) (kzg.KZG1Settings, kzg.KZG2Settings, error) {
+		return kzg.KZG1Settings{}, kzg.KZG2Settings{}, err
+	}
 
-			_, err = f.Read(dataG1)
-			check(err)
-			kzg2.VK[i].Deserialize(dataG1)
+	// Read VK
+	for i := uint64(0); i < 2; i++ {
+		if err := readElement(r, dataG2, fileName, "KZG1 VK", i); err != nil {
+			return fail(err)
+		}
+		if err := kzg1.VK[i].Deserialize(dataG2); err != nil {
+			return fail(fmt.Errorf("%s: KZG1 VK[%d]: %w", fileName, i, err))
+		}
+		if err := readElement(r, dataG1, fileName, "KZG2 VK", i); err != nil {
+			return fail(err)
+		}
+		if err := kzg2.VK[i].Deserialize(dataG1); err != nil {
+			return fail(fmt.Errorf("%s: KZG2 VK[%d]: %w", fileName, i, err))
 		}
 	}
 	// Read PK
-	{
-		for i := uint64(0); i < kzgM; i++ {
-			_, err = f.Read(dataG1)
-			check(err)
-			kzg1.PK[i].Deserialize(dataG1)
-
-			_, err = f.Read(dataG2)
-			check(err)
-			kzg2.PK[i].Deserialize(dataG2)
+	for i := uint64(0); i < kzgM; i++ {
+		if err := readElement(r, dataG1, fileName, "KZG1 PK", i); err != nil {
+			return fail(err)
+		}
+		if err := kzg1.PK[i].Deserialize(dataG1); err != nil {
+			return fail(fmt.Errorf("%s: KZG1 PK[%d]: %w", fileName, i, err))
+		}
+		if err := readElement(r, dataG2, fileName, "KZG2 PK", i); err != nil {
+			return fail(err)
+		}
+		if err := kzg2.PK[i].Deserialize(dataG2); err != nil {
+			return fail(fmt.Errorf("%s: KZG2 PK[%d]: %w", fileName, i, err))
 		}
 	}
-	defer f.Close()
-	return ck, kzg1, kzg2
+	return kzg1, kzg2, nil
 }
 
+// LoadKeys memory-maps the keys in folderPath and deserializes the first M of them in parallel.
+// The digest of a v2 file is checked. Panics on error, see LoadKeysMapped.
 func LoadKeys(M uint64, folderPath string) (Ck, kzg.KZG1Settings, kzg.KZG2Settings) {
 
-	ck, kzg1, kzg2 := IPPCMLoadCmKzg(M, folderPath)
+	ck, kzg1, kzg2, err := LoadKeysMapped(folderPath, M)
+	check(err)
 	return ck, kzg1, kzg2
 }
@@ -0,0 +1,142 @@
+package cm
+
+import (
+	"fmt"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// KeyError reports the first position at which the keys do not have the structure promised by IPPSetupKZG.
+type KeyError struct {
+	Section string
+	Index   uint64
+	Reason  string
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("keys: %s[%d]: %s", e.Section, e.Index, e.Reason)
+}
+
+// ValidateKeys checks that ck, kzg1 and kzg2 have the structure IPPSetupKZG promises:
+// - kzg1.PK[i] = g^{alpha^i} and kzg1.VK = (h, h^alpha)
+// - kzg2.PK[i] = h^{beta^i} and kzg2.VK = (g, g^beta)
+// - ck.W[i] = kzg1.PK[2i] and ck.V[i] = kzg2.PK[2i]
+// The powers are checked with random linear combinations: one G1 MSM and one G2 MSM per side, and a single multi-pairing.
+// On failure, the first inconsistent index is located with a binary search and returned as a *KeyError.
+func ValidateKeys(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) error {
+
+	if len(kzg1.VK) != 2 || len(kzg2.VK) != 2 {
+		return &KeyError{"VK", 0, fmt.Sprintf("VK sizes are %d and %d, expected 2", len(kzg1.VK), len(kzg2.VK))}
+	}
+	n := len(kzg1.PK)
+	if n != len(kzg2.PK) {
+		return &KeyError{"KZG2 PK", uint64(len(kzg2.PK)), fmt.Sprintf("KZG1 PK has %d elements", n)}
+	}
+	if ck.M < 1 || uint64(len(ck.W)) != ck.M || uint64(len(ck.V)) != ck.M {
+		return &KeyError{"W", 0, fmt.Sprintf("invalid ck size: %d %d %d", ck.M, len(ck.W), len(ck.V))}
+	}
+	if uint64(n) < 2*ck.M-1 {
+		return &KeyError{"KZG1 PK", uint64(n), fmt.Sprintf("need %d elements for a ck of size %d", 2*ck.M-1, ck.M)}
+	}
+
+	// Generators and the trapdoor in the VKs
+	if kzg1.PK[0].IsZero() || kzg2.PK[0].IsZero() {
+		return &KeyError{"KZG1 PK", 0, "generator is the identity"}
+	}
+	if kzg1.VK[1].IsZero() || kzg2.VK[1].IsZero() {
+		return &KeyError{"VK", 1, "trapdoor is zero"}
+	}
+	if !kzg2.VK[0].IsEqual(&kzg1.PK[0]) {
+		return &KeyError{"KZG2 VK", 0, "does not match KZG1 PK[0]"}
+	}
+	if !kzg1.VK[0].IsEqual(&kzg2.PK[0]) {
+		return &KeyError{"KZG1 VK", 0, "does not match KZG2 PK[0]"}
+	}
+
+	// ck is the even-index subset of the KZG PKs
+	for i := uint64(0); i < ck.M; i++ {
+		if !ck.W[i].IsEqual(&kzg1.PK[2*i]) {
+			return &KeyError{"W", i, fmt.Sprintf("does not match KZG1 PK[%d]", 2*i)}
+		}
+		if !ck.V[i].IsEqual(&kzg2.PK[2*i]) {
+			return &KeyError{"V", i, fmt.Sprintf("does not match KZG2 PK[%d]", 2*i)}
+		}
+	}
+
+	// Consecutive powers, both chains in one multi-pairing.
+	P1, Q1 := g1PowersTerms(kzg1, 0, n-1)
+	P2, Q2 := g2PowersTerms(kzg2, 0, n-1)
+	if isPairingProductOne(append(P1, P2...), append(Q1, Q2...)) {
+		return nil
+	}
+
+	if !isPairingProductOne(g1PowersTerms(kzg1, 0, n-1)) {
+		return &KeyError{"KZG1 PK", firstFailure(n-1, func(lo, hi int) bool {
+			return isPairingProductOne(g1PowersTerms(kzg1, lo, hi))
+		}) + 1, "not the next power of alpha"}
+	}
+	return &KeyError{"KZG2 PK", firstFailure(n-1, func(lo, hi int) bool {
+		return isPairingProductOne(g2PowersTerms(kzg2, lo, hi))
+	}) + 1, "not the next power of beta"}
+}
+
+// g1PowersTerms returns the pairing terms of the relations PK[i+1] = PK[i]^alpha for i in [lo, hi).
+// With random r_i: e(sum r_i PK[i+1], h) * e(-sum r_i PK[i], h^alpha) == 1
+func g1PowersTerms(kzg1 *kzg.KZG1Settings, lo int, hi int) ([]mcl.G1, []mcl.G2) {
+	P := make([]mcl.G1, 2)
+	Q := []mcl.G2{kzg1.VK[0], kzg1.VK[1]}
+	if hi <= lo {
+		return P, Q
+	}
+	r := randomScalars(hi - lo)
+	mcl.G1MulVec(&P[0], kzg1.PK[lo+1:hi+1], r)
+	mcl.G1MulVec(&P[1], kzg1.PK[lo:hi], r)
+	mcl.G1Neg(&P[1], &P[1])
+	return P, Q
+}
+
+// g2PowersTerms returns the pairing terms of the relations PK[i+1] = PK[i]^beta for i in [lo, hi).
+// With random r_i: e(g, sum r_i PK[i+1]) * e(-g^beta, sum r_i PK[i]) == 1
+func g2PowersTerms(kzg2 *kzg.KZG2Settings, lo int, hi int) ([]mcl.G1, []mcl.G2) {
+	P := []mcl.G1{kzg2.VK[0], kzg2.VK[1]}
+	Q := make([]mcl.G2, 2)
+	mcl.G1Neg(&P[1], &P[1])
+	if hi <= lo {
+		return P, Q
+	}
+	r := randomScalars(hi - lo)
+	mcl.G2MulVec(&Q[0], kzg2.PK[lo+1:hi+1], r)
+	mcl.G2MulVec(&Q[1], kzg2.PK[lo:hi], r)
+	return P, Q
+}
+
+func randomScalars(n int) []mcl.Fr {
+	r := make([]mcl.Fr, n)
+	for i := range r {
+		r[i].Random()
+	}
+	return r
+}
+
+func isPairingProductOne(P []mcl.G1, Q []mcl.G2) bool {
+	var e mcl.GT
+	mcl.MillerLoopVec(&e, P, Q)
+	mcl.FinalExp(&e, &e)
+	return e.IsOne()
+}
+
+// firstFailure finds the smallest index in [0, n) at which a relation fails.
+// ok(lo, hi) checks all the relations in [lo, hi) at once. Assumes ok(0, n) is false.
+func firstFailure(n int, ok func(lo int, hi int) bool) uint64 {
+	lo, hi := 0, n
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if !ok(lo, mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return uint64(lo)
+}
@@ -203,6 +203,24 @@ func CkFold(result *Ck, x mcl.Fr, xInv mcl.Fr, ck *Ck) {
 	// return result
 }
 
+// CkFoldInPlace computes ck' as CkFold, but in place: V' and W' are written over V_L and W_L,
+// and ck is resliced to the left halves. Nothing is allocated.
+// The folds are split among workers goroutines, see utils.G1FoldInto.
+func CkFoldInPlace(ck *Ck, x mcl.Fr, xInv mcl.Fr, workers int) {
+
+	MPrime := ck.M / 2
+	V_L := ck.V[:MPrime]
+	V_R := ck.V[MPrime:]
+	W_L := ck.W[:MPrime]
+	W_R := ck.W[MPrime:]
+
+	utils.G2FoldInto(V_L, xInv, V_R, V_L, workers)
+	utils.G1FoldInto(W_L, x, W_R, W_L, workers)
+	ck.M = MPrime
+	ck.V = V_L
+	ck.W = W_L
+}
+
 // ComFold computes the new commitment for the next level of recursion.
 // Given com, ComL, ComR computes com' = ComL^x * com * ComR^(x^-1)
 //
@@ -239,3 +257,23 @@ func ComFold(x mcl.Fr, xInv mcl.Fr, ComL *Com, C *Com, ComR *Com) Com {
 
 	return result
 }
+
+// ComFoldAll computes the commitment after all the rounds at once.
+// Since ComFold multiplies com by ComL^x and ComR^(x^-1), after the rounds k:
+// com'[j] = com[j] * prod_k L_k[j]^(x_k) * R_k[j]^(x_k^-1)
+// Each component is one multi-exponentiation with cyclotomic squarings, see utils.GTFoldAll.
+// L and R have to be in GT, which utils.ReadGT and utils.GTFromHex check when decoding a proof.
+func ComFoldAll(C *Com, L []Com, R []Com, X []mcl.Fr) Com {
+
+	rowsL := make([][]mcl.GT, len(L))
+	rowsR := make([][]mcl.GT, len(R))
+	for k := range L {
+		rowsL[k] = L[k].Com[:]
+	}
+	for k := range R {
+		rowsR[k] = R[k].Com[:]
+	}
+	result := Com{}
+	copy(result.Com[:], utils.GTFoldAll(C.Com[:], rowsL, rowsR, X))
+	return result
+}
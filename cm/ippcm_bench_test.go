@@ -1 +1,49 @@
 package cm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// BenchmarkCmComFold compares the folding of the verifier, ComFoldAll, to ComFold round by round.
+func BenchmarkCmComFold(b *testing.B) {
+	mcl.InitFromString("bls12-381")
+
+	for _, l := range []int{8, 12, 16} {
+		A, B := utils.GenerateData(uint64(l))
+		X := make([]mcl.Fr, l)
+		XInv := make([]mcl.Fr, l)
+		L := make([]Com, l)
+		R := make([]Com, l)
+		var com Com
+		for j := range com.Com {
+			mcl.Pairing(&com.Com[j], &A[j], &B[j])
+		}
+		for k := 0; k < l; k++ {
+			X[k].Random()
+			mcl.FrInv(&XInv[k], &X[k])
+			for j := range L[k].Com {
+				mcl.Pairing(&L[k].Com[j], &A[k], &B[j])
+				mcl.Pairing(&R[k].Com[j], &A[j], &B[k])
+			}
+		}
+
+		b.Run(fmt.Sprintf("%d/ComFold;", l), func(b *testing.B) {
+			for bn := 0; bn < b.N; bn++ {
+				folded := com
+				for k := 0; k < l; k++ {
+					folded = ComFold(X[k], XInv[k], &L[k], &folded, &R[k])
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d/ComFoldAll;", l), func(b *testing.B) {
+			for bn := 0; bn < b.N; bn++ {
+				ComFoldAll(&com, L, R, X)
+			}
+		})
+	}
+}
@@ -1,7 +1,16 @@
 package cm
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/alinush/go-mcl"
@@ -113,6 +122,38 @@ func TestCm(t *testing.T) {
 		if len((ckPrime).W) != int(ckPrime.M) {
 			t.Errorf("Ck Fold: CkPrime V is not same size!")
 		}
+
+		ckInPlace := Ck{}
+		ckInPlace.Clone(ck)
+		CkFoldInPlace(&ckInPlace, x, xInv, 4)
+		if ckInPlace.M != ckPrime.M {
+			t.Errorf("Ck Fold: In place size is not expected!")
+		}
+		if !utils.G2SliceIsEqual(ckInPlace.V, ckPrime.V) || !utils.G1SliceIsEqual(ckInPlace.W, ckPrime.W) {
+			t.Errorf("Ck Fold: In place fold differs from CkFold!")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/ComFoldAll;", M), func(t *testing.T) {
+		l := 3
+		X := make([]mcl.Fr, l)
+		L := make([]Com, l)
+		R := make([]Com, l)
+		folded := com
+		for k := 0; k < l; k++ {
+			X[k].Random()
+			for j := range L[k].Com {
+				mcl.Pairing(&L[k].Com[j], &A[3*k+j], &B[0])
+				mcl.Pairing(&R[k].Com[j], &A[0], &B[3*k+j])
+			}
+			var xInv mcl.Fr
+			mcl.FrInv(&xInv, &X[k])
+			folded = ComFold(X[k], xInv, &L[k], &folded, &R[k])
+		}
+		got := ComFoldAll(&com, L, R, X)
+		if !got.IsEqual(&folded) {
+			t.Errorf("ComFoldAll differs from ComFold round by round!")
+		}
 	})
 }
 
@@ -210,3 +251,347 @@ func SumGeomProgression(n uint64, a mcl.Fr, r mcl.Fr) mcl.Fr {
 	mcl.FrDiv(&result, &result, &denom)
 	return result
 }
+
+func TestCmJSON(t *testing.T) {
+
+	M := uint64(1) << 4
+	ck, _, _, _, _, A, B, Z := GenerateIppcmData(M)
+	com := IPPCM(ck, A, B, Z)
+
+	data, err := json.Marshal(com)
+	if err != nil {
+		t.Fatalf("Com JSON: Marshal failed: %v", err)
+	}
+	var comDecoded Com
+	if err := json.Unmarshal(data, &comDecoded); err != nil {
+		t.Fatalf("Com JSON: Unmarshal failed: %v", err)
+	}
+	if !comDecoded.IsEqual(&com) {
+		t.Errorf("Com JSON: Round trip failed")
+	}
+
+	data, err = json.Marshal(ck)
+	if err != nil {
+		t.Fatalf("Ck JSON: Marshal failed: %v", err)
+	}
+	var ckDecoded Ck
+	if err := json.Unmarshal(data, &ckDecoded); err != nil {
+		t.Fatalf("Ck JSON: Unmarshal failed: %v", err)
+	}
+	if ckDecoded.M != ck.M || !utils.G1SliceIsEqual(ckDecoded.W, ck.W) || !utils.G2SliceIsEqual(ckDecoded.V, ck.V) {
+		t.Errorf("Ck JSON: Round trip failed")
+	}
+}
+
+func TestCmLoadErrors(t *testing.T) {
+
+	MN := uint64(1) << 4
+	ck, kzg1, kzg2, _, _, _, _, _, _, _ := GenerateIppcmKzgData(MN)
+	folderPath := t.TempDir()
+	IPPSaveCmKzg(ck, kzg1, kzg2, folderPath)
+
+	t.Run(fmt.Sprintf("%d/Load;", MN), func(t *testing.T) {
+		ckLoaded, kzg1Loaded, kzg2Loaded, err := LoadCkKzg(folderPath, MN/2)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if !utils.G1SliceIsEqual(ckLoaded.W, ck.W[:MN/2]) || !utils.G2SliceIsEqual(ckLoaded.V, ck.V[:MN/2]) {
+			t.Errorf("Loaded CK does not match")
+		}
+		if !utils.G1SliceIsEqual(kzg1Loaded.PK, kzg1.PK[:MN-1]) || !utils.G2SliceIsEqual(kzg2Loaded.PK, kzg2.PK[:MN-1]) {
+			t.Errorf("Loaded KZG PK does not match")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/Reject;", MN), func(t *testing.T) {
+		if _, err := LoadCk(folderPath, 2*MN); err == nil {
+			t.Errorf("Oversized request not detected")
+		}
+		if _, err := LoadCk(folderPath, 3); err == nil {
+			t.Errorf("Non power of 2 request not detected")
+		}
+		if _, err := LoadCk(t.TempDir(), MN); err == nil {
+			t.Errorf("Missing file not detected")
+		}
+
+		fileName := folderPath + "/CK.data"
+		data, _ := ioutil.ReadFile(fileName)
+		ioutil.WriteFile(fileName, data[:len(data)-1], 0644)
+		if _, err := LoadCk(folderPath, MN); err == nil {
+			t.Errorf("Truncated file not detected")
+		}
+
+		for i := 8; i < 8+utils.GetG1ByteSize(); i++ {
+			data[i] = 0xff // Corrupt W[0], x is out of range
+		}
+		ioutil.WriteFile(fileName, data, 0644)
+		if _, err := LoadCk(folderPath, MN); err == nil || !strings.Contains(err.Error(), "W[0]") {
+			t.Errorf("Corrupt element not reported: %v", err)
+		}
+	})
+}
+
+func TestCmKeyContainer(t *testing.T) {
+
+	MN := uint64(1) << 4
+	ck, kzg1, kzg2, _, _, _, _, _, _, _ := GenerateIppcmKzgData(MN)
+	folderPath := t.TempDir()
+	if err := SaveKeyContainer(folderPath, ck, kzg1, kzg2); err != nil {
+		t.Fatalf("Key container: Save failed: %v", err)
+	}
+
+	t.Run(fmt.Sprintf("%d/Load;", MN), func(t *testing.T) {
+		ckLoaded, kzg1Loaded, kzg2Loaded, err := LoadCkKzg(folderPath, MN/2)
+		if err != nil {
+			t.Fatalf("Key container: Load failed: %v", err)
+		}
+		if !utils.G1SliceIsEqual(ckLoaded.W, ck.W[:MN/2]) || !utils.G2SliceIsEqual(ckLoaded.V, ck.V[:MN/2]) {
+			t.Errorf("Key container: Loaded CK does not match")
+		}
+		if !utils.G1SliceIsEqual(kzg1Loaded.PK, kzg1.PK[:MN-1]) || !utils.G2SliceIsEqual(kzg2Loaded.PK, kzg2.PK[:MN-1]) {
+			t.Errorf("Key container: Loaded KZG PK does not match")
+		}
+		if !utils.G2SliceIsEqual(kzg1Loaded.VK, kzg1.VK) || !utils.G1SliceIsEqual(kzg2Loaded.VK, kzg2.VK) {
+			t.Errorf("Key container: Loaded KZG VK does not match")
+		}
+
+		f, _ := os.Open(folderPath + "/" + KeyContainerFile)
+		header, err := ReadKeyHeader(f)
+		f.Close()
+		if err != nil || header.Flags&KeyFlagEvenPowers == 0 {
+			t.Errorf("Key container: Even powers flag is not set: %v", err)
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/Reject;", MN), func(t *testing.T) {
+		fileName := folderPath + "/" + KeyContainerFile
+		data, _ := ioutil.ReadFile(fileName)
+
+		data[13] ^= 1 // Reserved byte, only the digest can catch this
+		ioutil.WriteFile(fileName, data, 0644)
+		if _, err := LoadCk(folderPath, MN); !errors.Is(err, ErrKeyDigest) {
+			t.Errorf("Key container: Digest mismatch not detected: %v", err)
+		}
+		data[13] ^= 1
+
+		ioutil.WriteFile(fileName, data[:len(data)-1], 0644)
+		if _, err := LoadCk(folderPath, MN); err == nil {
+			t.Errorf("Key container: Truncated file not detected")
+		}
+	})
+}
+
+func TestCmKeyFileMapped(t *testing.T) {
+
+	MN := uint64(1) << 4
+	ck, kzg1, kzg2, _, _, _, _, _, _, _ := GenerateIppcmKzgData(MN)
+	folderV1 := t.TempDir()
+	folderV2 := t.TempDir()
+	IPPSaveCmKzg(ck, kzg1, kzg2, folderV1)
+	if err := SaveKeyContainer(folderV2, ck, kzg1, kzg2); err != nil {
+		t.Fatalf("Key container: Save failed: %v", err)
+	}
+
+	for _, folderPath := range []string{folderV1, folderV2} {
+		ckLoaded, kzg1Loaded, kzg2Loaded, err := LoadKeysMapped(folderPath, MN/4)
+		if err != nil {
+			t.Fatalf("Mapped load failed: %v", err)
+		}
+		if !utils.G1SliceIsEqual(ckLoaded.W, ck.W[:MN/4]) || !utils.G2SliceIsEqual(ckLoaded.V, ck.V[:MN/4]) {
+			t.Errorf("Mapped CK does not match")
+		}
+		if !utils.G1SliceIsEqual(kzg1Loaded.PK, kzg1.PK[:MN/2-1]) || !utils.G2SliceIsEqual(kzg2Loaded.PK, kzg2.PK[:MN/2-1]) {
+			t.Errorf("Mapped KZG PK does not match")
+		}
+		if !utils.G2SliceIsEqual(kzg1Loaded.VK, kzg1.VK) || !utils.G1SliceIsEqual(kzg2Loaded.VK, kzg2.VK) {
+			t.Errorf("Mapped KZG VK does not match")
+		}
+
+		keyFile, err := OpenKeyFile(folderPath)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		W := make([]mcl.G1, 3)
+		if err := keyFile.ReadW(5, W); err != nil || !utils.G1SliceIsEqual(W, ck.W[5:8]) {
+			t.Errorf("Range W[5:8] does not match: %v", err)
+		}
+		if err := keyFile.ReadW(MN-1, W); err == nil {
+			t.Errorf("Out of range read not detected")
+		}
+		if keyFile.Version == KeyContainerVersion {
+			if err := keyFile.VerifyDigest(); err != nil {
+				t.Errorf("Digest check failed: %v", err)
+			}
+		}
+		if _, _, err := keyFile.KZG(3); err == nil {
+			t.Errorf("KZG keys of size 3 not rejected")
+		}
+		keyFile.Close()
+	}
+
+	fileName := folderV2 + "/" + KeyContainerFile
+	data, _ := ioutil.ReadFile(fileName)
+	data[13] ^= 1 // Reserved byte, only the digest can catch this
+	ioutil.WriteFile(fileName, data, 0644)
+	if _, _, _, err := LoadKeysMapped(folderV2, MN/4); !errors.Is(err, ErrKeyDigest) {
+		t.Errorf("Mapped load: Digest mismatch not detected: %v", err)
+	}
+	if _, _, _, err := LoadKeysMappedUnverified(folderV2, MN/4); err != nil {
+		t.Errorf("Unverified mapped load failed: %v", err)
+	}
+}
+
+func TestCmValidateKeys(t *testing.T) {
+
+	MN := uint64(1) << 5
+	ck, kzg1, kzg2, _, _, _, _, _, _, _ := GenerateIppcmKzgData(MN)
+
+	if err := ValidateKeys(ck, kzg1, kzg2); err != nil {
+		t.Fatalf("ValidateKeys rejected honest keys: %v", err)
+	}
+
+	var tests = []struct {
+		section string
+		index   uint64
+		tamper  func(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings)
+	}{
+		{"KZG1 PK", 5, func(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) { kzg1.PK[5].Random() }},
+		{"KZG2 PK", 2*MN - 3, func(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) { kzg2.PK[2*MN-3].Random() }},
+		{"W", 3, func(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) { ck.W[3].Random() }},
+		{"V", 0, func(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) { ck.V[0].Random() }},
+		{"KZG2 VK", 0, func(ck *Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings) { kzg2.VK[0].Random() }},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%d;", tt.section, tt.index), func(t *testing.T) {
+			ckBad := Ck{}
+			ckBad.Clone(ck)
+			kzg1Bad := kzg.KZG1Settings{PK: append([]mcl.G1{}, kzg1.PK...), VK: append([]mcl.G2{}, kzg1.VK...)}
+			kzg2Bad := kzg.KZG2Settings{PK: append([]mcl.G2{}, kzg2.PK...), VK: append([]mcl.G1{}, kzg2.VK...)}
+			tt.tamper(&ckBad, &kzg1Bad, &kzg2Bad)
+
+			err := ValidateKeys(&ckBad, &kzg1Bad, &kzg2Bad)
+			var keyErr *KeyError
+			if !errors.As(err, &keyErr) {
+				t.Fatalf("Expected a KeyError, got %v", err)
+			}
+			if keyErr.Section != tt.section || keyErr.Index != tt.index {
+				t.Errorf("Expected %s[%d], got %v", tt.section, tt.index, keyErr)
+			}
+		})
+	}
+}
+
+func TestCmImportPtau(t *testing.T) {
+
+	MN := uint64(1) << 3
+	power := uint32(4) // 2^power >= 2MN - 1 powers in G2
+	folderPath := t.TempDir()
+
+	writeTranscript := func(fileName string, tau mcl.Fr, g mcl.G1, h mcl.G2) {
+		tauG1 := make([]mcl.G1, 2*(1<<power)-1)
+		tauG2 := make([]mcl.G2, 1<<power)
+		var x mcl.Fr
+		x.SetInt64(1)
+		for i := range tauG1 {
+			mcl.G1Mul(&tauG1[i], &g, &x)
+			if i < len(tauG2) {
+				mcl.G2Mul(&tauG2[i], &h, &x)
+			}
+			mcl.FrMul(&x, &x, &tau)
+		}
+		f, _ := os.Create(fileName)
+		defer f.Close()
+		if err := writePtau(f, power, tauG1, tauG2); err != nil {
+			t.Fatalf("Writing ptau failed: %v", err)
+		}
+	}
+
+	alpha, beta, g, h := utils.RunMPC()
+	writeTranscript(folderPath+"/alpha.ptau", alpha, g, h)
+	writeTranscript(folderPath+"/beta.ptau", beta, g, h)
+
+	ck, kzg1, kzg2, err := ImportPtau(folderPath+"/alpha.ptau", folderPath+"/beta.ptau", MN)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	ckWant, kzg1Want, kzg2Want := IPPSetupKZG(MN, alpha, beta, g, h)
+	if !utils.G1SliceIsEqual(ck.W, ckWant.W) || !utils.G2SliceIsEqual(ck.V, ckWant.V) {
+		t.Errorf("Imported ck does not match IPPSetupKZG")
+	}
+	if !utils.G1SliceIsEqual(kzg1.PK, kzg1Want.PK) || !utils.G2SliceIsEqual(kzg1.VK, kzg1Want.VK) {
+		t.Errorf("Imported KZG1 does not match IPPSetupKZG")
+	}
+	if !utils.G2SliceIsEqual(kzg2.PK, kzg2Want.PK) || !utils.G1SliceIsEqual(kzg2.VK, kzg2Want.VK) {
+		t.Errorf("Imported KZG2 does not match IPPSetupKZG")
+	}
+
+	// alpha = beta, the commitment is not binding
+	if _, _, _, err := ImportPtau(folderPath+"/alpha.ptau", folderPath+"/alpha.ptau", MN); err == nil {
+		t.Errorf("Single trapdoor import not detected")
+	}
+	writeTranscript(folderPath+"/alpha-copy.ptau", alpha, g, h)
+	if _, _, _, err := ImportPtau(folderPath+"/alpha.ptau", folderPath+"/alpha-copy.ptau", MN); err == nil {
+		t.Errorf("Single trapdoor import from a copy not detected")
+	}
+	// Not enough powers
+	if _, _, _, err := ImportPtau(folderPath+"/alpha.ptau", folderPath+"/beta.ptau", 4*MN); err == nil {
+		t.Errorf("Oversized import not detected")
+	}
+}
+
+// writePtau produces a small .ptau transcript for the import tests.
+func writePtau(w io.Writer, power uint32, tauG1 []mcl.G1, tauG2 []mcl.G2) error {
+
+	var q, r big.Int
+	q.SetString(mcl.GetFieldOrder(), 10)
+	r.Lsh(big.NewInt(1), 8*ptauFieldSize)
+	toMontgomery := func(buf *bytes.Buffer, s string) {
+		var x big.Int
+		x.SetString(s, 10)
+		x.Mul(&x, &r)
+		x.Mod(&x, &q)
+		data := make([]byte, ptauFieldSize)
+		x.FillBytes(data)
+		buf.Write(reverseBytes(data))
+	}
+	coordinates := func(s string) []string {
+		fields := bytes.Fields([]byte(s))
+		out := make([]string, len(fields)-1)
+		for i := range out {
+			out[i] = string(fields[i+1])
+		}
+		return out
+	}
+
+	var header, g1, g2 bytes.Buffer
+	binary.Write(&header, binary.LittleEndian, uint32(ptauFieldSize))
+	qBytes := make([]byte, ptauFieldSize)
+	q.FillBytes(qBytes)
+	header.Write(reverseBytes(qBytes))
+	binary.Write(&header, binary.LittleEndian, power)
+	binary.Write(&header, binary.LittleEndian, power)
+	for i := range tauG1 {
+		mcl.G1Normalize(&tauG1[i], &tauG1[i])
+		for _, c := range coordinates(tauG1[i].GetString(10)) {
+			toMontgomery(&g1, c)
+		}
+	}
+	for i := range tauG2 {
+		mcl.G2Normalize(&tauG2[i], &tauG2[i])
+		for _, c := range coordinates(tauG2[i].GetString(10)) {
+			toMontgomery(&g2, c)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("ptau")
+	binary.Write(&out, binary.LittleEndian, uint32(1))
+	binary.Write(&out, binary.LittleEndian, uint32(3))
+	for i, section := range []*bytes.Buffer{&header, &g1, &g2} {
+		binary.Write(&out, binary.LittleEndian, uint32(i+1))
+		binary.Write(&out, binary.LittleEndian, uint64(section.Len()))
+		out.Write(section.Bytes())
+	}
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,77 @@
+package gipa
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// BatchVerify is a member function of Verifier
+// It checks proofs[i] against the statement coms[i], with the ck and M of the verifier.
+// Each transcript is replayed as in Verify. The final checks com == (e(A, V), e(W, B), e(A, B))
+// are combined with random coefficients c into a single multi-pairing:
+// prod_i com_i^c_i == prod_i e(A_i, c_i0 V_i + c_i2 B_i) e(c_i1 W_i, B_i)
+// Parameters
+// ----------
+// coms, slice of cm.Com, one statement per proof
+// proofs, slice of Proof
+//
+// Returns
+// -------
+// bool, true if all the proofs verify
+// int, index of the first proof which fails, -1 if all the proofs verify, or if the batch failed
+// but the culprit is not located (see utils.LocateFailure)
+func (self *Verifier) BatchVerify(coms []cm.Com, proofs []Proof) (bool, int) {
+
+	utils.SizeMismatchCheck(uint64(len(coms)), uint64(len(proofs)), "GIPA BatchVerify: Proofs Size:")
+
+	n := len(proofs)
+	if n == 0 {
+		return true, -1
+	}
+	verifiers := make([]Verifier, n)
+	P := make([]mcl.G1, 0, 2*n)
+	Q := make([]mcl.G2, 0, 2*n)
+	var lhs mcl.GT
+	lhs.SetInt64(1)
+
+	for i := range proofs {
+		// FoldCk does not write to ck, thus it can be shared
+		verifiers[i] = Verifier{M: self.M, Ck: self.Ck, Com: coms[i], Workers: self.Workers}
+		if !verifiers[i].Reduce(proofs[i]) {
+			return false, i
+		}
+
+		var c [3]mcl.Fr
+		var temp mcl.GT
+		for k := range c {
+			c[k].Random()
+			mcl.GTPow(&temp, &verifiers[i].Com.Com[k], &c[k])
+			mcl.GTMul(&lhs, &lhs, &temp)
+		}
+
+		A := proofs[i].A[0]
+		B := proofs[i].B[0]
+		var cW mcl.G1
+		var cV, cB mcl.G2
+		mcl.G2Mul(&cV, &verifiers[i].Ck.V[0], &c[0])
+		mcl.G2Mul(&cB, &B, &c[2])
+		mcl.G2Add(&cV, &cV, &cB)
+		mcl.G1Mul(&cW, &verifiers[i].Ck.W[0], &c[1])
+
+		P = append(P, A, cW)
+		Q = append(Q, cV, B)
+	}
+
+	var rhs mcl.GT
+	mcl.MillerLoopVec(&rhs, P, Q)
+	mcl.FinalExp(&rhs, &rhs)
+	if lhs.IsEqual(&rhs) {
+		return true, -1
+	}
+
+	// Find the culprit. The transcripts are already replayed.
+	return utils.LocateFailure(n, func(i int) bool {
+		return verifiers[i].Check(proofs[i].A[:1], proofs[i].B[:1])
+	})
+}
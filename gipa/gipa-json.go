@@ -0,0 +1,54 @@
+package gipa
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+type proofJSON struct {
+	utils.WireHeaderJSON
+	Rounds []cm.ComRoundJSON `json:"Rounds"`
+	A      string            `json:"A"`
+	B      string            `json:"B"`
+}
+
+// MarshalJSON encodes the proof with hex group elements and one entry per round.
+func (self Proof) MarshalJSON() ([]byte, error) {
+	rounds, err := cm.RoundsToJSON(self.L, self.R)
+	if err != nil {
+		return nil, fmt.Errorf("GIPA Proof: %w", err)
+	}
+	out := proofJSON{
+		utils.NewWireHeaderJSON(utils.ProofTypeGipa),
+		rounds,
+		utils.G1ToHex(&self.A[0]),
+		utils.G2ToHex(&self.B[0]),
+	}
+	return json.Marshal(out)
+}
+
+func (self *Proof) UnmarshalJSON(data []byte) error {
+	var in proofJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("GIPA Proof: %w", err)
+	}
+	if err := in.Check(utils.ProofTypeGipa); err != nil {
+		return fmt.Errorf("GIPA Proof: %w", err)
+	}
+	L, R, err := cm.RoundsFromJSON(in.Rounds)
+	if err != nil {
+		return fmt.Errorf("GIPA Proof: %w", err)
+	}
+	proof := Proof{L: L, R: R}
+	if err := utils.G1FromHex(&proof.A[0], in.A); err != nil {
+		return fmt.Errorf("GIPA Proof: A: %w", err)
+	}
+	if err := utils.G2FromHex(&proof.B[0], in.B); err != nil {
+		return fmt.Errorf("GIPA Proof: B: %w", err)
+	}
+	*self = proof
+	return nil
+}
@@ -1,8 +1,10 @@
 package gipa
 
 import (
+	"context"
 	"fmt"
 	"math/bits"
+	"time"
 
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/cm"
@@ -33,6 +35,14 @@ type Prover struct {
 
 	Transcript       [32]byte
 	RandomChallenges []mcl.Fr
+
+	// Workers is the number of goroutines used in each round. 1 runs sequentially.
+	// 0 picks it from the size of the round, see utils.InnerProd and utils.G1FoldInto.
+	// The proof does not depend on it.
+	Workers int
+
+	// Progress, if not nil, is called after each phase of ProveContext and Prove.
+	Progress utils.ProgressFunc
 }
 
 // Transform is a member function of Prover
@@ -51,6 +61,13 @@ type Prover struct {
 // Updates the data members ComL and ComR.
 // Returns ComL, ComR so that verifier can pose the challenge
 func (self *Prover) Transform() (cm.Com, cm.Com) {
+	ComL, ComR, _ := self.TransformContext(context.Background())
+	return ComL, ComR
+}
+
+// TransformContext is Transform, which stops the pairing products once ctx is done, see utils.InnerProdsContext.
+// On error, the prover state is left half transformed.
+func (self *Prover) TransformContext(ctx context.Context) (cm.Com, cm.Com, error) {
 	MPrime := self.M / 2
 	self.MPrime = MPrime
 	self.A_L = self.A[:MPrime]
@@ -58,14 +75,23 @@ func (self *Prover) Transform() (cm.Com, cm.Com) {
 	self.B_L = self.B[:MPrime]
 	self.B_R = self.B[MPrime:]
 
-	self.Z_L = utils.InnerProd(self.A_R, self.B_L)
-	self.Z_R = utils.InnerProd(self.A_L, self.B_R)
-
 	self.Ck.Transform(&self.Ck1, &self.Ck2)
-	self.ComL = cm.IPPCM(&self.Ck1, self.A_R, self.B_L, self.Z_L)
-	self.ComR = cm.IPPCM(&self.Ck2, self.A_L, self.B_R, self.Z_R)
 
-	return self.ComL, self.ComR
+	// The six pairing products are independent: Z_L, Z_R, then (<A, V>, <W, B>) of ComL and ComR
+	prods, err := utils.InnerProdsContext(ctx,
+		[][]mcl.G1{self.A_R, self.A_L, self.A_R, self.Ck1.W, self.A_L, self.Ck2.W},
+		[][]mcl.G2{self.B_L, self.B_R, self.Ck1.V, self.B_L, self.Ck2.V, self.B_R},
+		self.Workers,
+	)
+	if err != nil {
+		return cm.Com{}, cm.Com{}, err
+	}
+	self.Z_L = prods[0]
+	self.Z_R = prods[1]
+	self.ComL = cm.Com{Com: [3]mcl.GT{prods[2], prods[3], self.Z_L}}
+	self.ComR = cm.Com{Com: [3]mcl.GT{prods[4], prods[5], self.Z_R}}
+
+	return self.ComL, self.ComR, nil
 }
 
 // Fold is a member function of Prover
@@ -86,10 +112,13 @@ func (self *Prover) Fold(x mcl.Fr) {
 	mcl.FrInv(&y, &x)
 
 	self.X = append(self.X, x)
-	self.A = utils.G1Fold(x, self.A_R, self.A_L)
-	self.B = utils.G2Fold(y, self.B_R, self.B_L)
+	// In place: the folded vectors overwrite the left halves
+	utils.G1FoldInto(self.A_L, x, self.A_R, self.A_L, self.Workers)
+	utils.G2FoldInto(self.B_L, y, self.B_R, self.B_L, self.Workers)
+	self.A = self.A_L
+	self.B = self.B_L
 
-	cm.CkFold(&self.Ck, x, y, &self.Ck)
+	cm.CkFoldInPlace(&self.Ck, x, y, self.Workers)
 	self.M = self.MPrime
 }
 
@@ -120,25 +149,45 @@ func (self *Prover) FiatShamir() mcl.Fr {
 // }
 
 func (self *Prover) Prove() Proof {
+	proof, _ := self.ProveContext(context.Background())
+	return proof
+}
+
+// ProveContext is Prove, which returns ctx.Err() once ctx is done.
+// ctx is checked between the phases of each round and inside the pairing products of Transform.
+// The prover state is consumed either way, use Clone to prove again.
+func (self *Prover) ProveContext(ctx context.Context) (Proof, error) {
 	var proof Proof
-	// proofSize := uint32(math.Ceil(math.Log2(float64(self.m)))) + 1
-	// x.New(proofSize)
 
 	m := self.M
 	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
 	i := 0
 	for m > 1 {
-		ComL, ComR := self.Transform()
+		if err := ctx.Err(); err != nil {
+			return Proof{}, err
+		}
+		start := time.Now()
+		ComL, ComR, err := self.TransformContext(ctx)
+		if err != nil {
+			return Proof{}, err
+		}
+		self.Progress.Report(i, m, utils.PhaseTransform, start)
 		proof.Append(ComL, ComR)
+
+		start = time.Now()
 		x := self.FiatShamir()
+		self.Progress.Report(i, m, utils.PhaseFiatShamir, start)
+
+		start = time.Now()
 		self.Fold(x)
+		self.Progress.Report(i, m, utils.PhaseFold, start)
 		m = m / 2
 		self.RandomChallenges[i] = x
 		i++
 	}
 	proof.A[0] = self.A[0]
 	proof.B[0] = self.B[0]
-	return proof
+	return proof, nil
 }
 
 func (self *Prover) Print() {
@@ -173,4 +222,6 @@ func (self *Prover) Clone(prover *Prover) {
 		&prover.Ck,
 		prover.A,
 		prover.B)
+	self.Workers = prover.Workers
+	self.Progress = prover.Progress
 }
@@ -2,6 +2,7 @@ package gipa
 
 import (
 	"fmt"
+	"math/bits"
 
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/cm"
@@ -23,6 +24,9 @@ type Verifier struct {
 	X []mcl.Fr
 
 	Transcript [32]byte
+
+	// Workers is the number of goroutines for the multi-exponentiations of FoldCk. 0 or 1 runs a single one.
+	Workers int
 }
 
 // Transform is a member function of Verifier
@@ -41,8 +45,7 @@ func (self *Verifier) Transform() {
 }
 
 // Fold is a member function of Verifier
-// Verifier computes the ck' and com'
-// Inner product of A', B' (prover state) should be equal to com'
+// Verifier records the challenge. com and ck are folded at once by FoldCom and FoldCk.
 // Parameters
 // ----------
 // x, Fr challenge posed to the prover
@@ -50,17 +53,34 @@ func (self *Verifier) Transform() {
 // Returns
 // -------
 // None
-// Updates the data members, com and ck
+// Updates the data members, M and X
 func (self *Verifier) Fold(x mcl.Fr) {
 
-	var y mcl.Fr
-	mcl.FrInv(&y, &x)
-
 	self.M = self.MPrime
 	self.X = append(self.X, x)
+}
+
+// FoldCom is a member function of Verifier
+// It folds com with the left and right commitments of all the rounds, see cm.ComFoldAll.
+// Inner product of A', B' (prover state) should be equal to com'
+// Call it once all the challenges are in X.
+func (self *Verifier) FoldCom(L []cm.Com, R []cm.Com) {
+	self.Com = cm.ComFoldAll(&self.Com, L, R, self.X)
+}
 
-	cm.CkFold(&self.Ck, x, y, &self.Ck)
-	self.Com = cm.ComFold(x, y, &self.ComL, &self.Com, &self.ComR)
+// FoldCk is a member function of Verifier
+// It replaces ck with the key of size 1 which folding ck with the challenges X would give.
+// That key is a multi-exponentiation of ck, see utils.FoldedScalars:
+// V' = sum s_i V_i with the inverse challenges, W' = sum t_i W_i with the challenges.
+// Thus it costs O(M) field operations and one MSM in each group, instead of O(M) scalar multiplications.
+// Call it once all the rounds are folded.
+func (self *Verifier) FoldCk() {
+
+	s := utils.FoldedScalars(self.X, true)
+	t := utils.FoldedScalars(self.X, false)
+	V := utils.G2MulVecWorkers(self.Ck.V, s, self.Workers)
+	W := utils.G1MulVecWorkers(self.Ck.W, t, self.Workers)
+	self.Ck = cm.Ck{M: 1, V: []mcl.G2{V}, W: []mcl.G1{W}}
 }
 
 func (self *Verifier) FiatShamir() mcl.Fr {
@@ -89,7 +109,22 @@ func (self *Verifier) FiatShamir() mcl.Fr {
 
 func (self *Verifier) Verify(proof Proof) bool {
 
+	if !self.Reduce(proof) {
+		return false
+	}
+	return self.Check(proof.A[:1], proof.B[:1])
+}
+
+// Reduce is a member function of Verifier
+// It replays the transcript of the proof, then folds com and ck down to size 1, see FoldCom and FoldCk.
+// Returns false if the proof has the wrong number of rounds.
+func (self *Verifier) Reduce(proof Proof) bool {
+
 	m := self.M
+	rounds := uint64(bits.Len64(m - 1))
+	if uint64(len(proof.L)) != rounds || uint64(len(proof.R)) != rounds {
+		return false
+	}
 	i := uint64(0)
 	for m > 1 {
 		self.Transform()
@@ -100,7 +135,9 @@ func (self *Verifier) Verify(proof Proof) bool {
 		m = m / 2
 		i = i + 1
 	}
-	return self.Check(proof.A[:1], proof.B[:1])
+	self.FoldCom(proof.L, proof.R)
+	self.FoldCk()
+	return true
 }
 
 func (self *Verifier) Update(ComL cm.Com, ComR cm.Com) {
@@ -170,4 +207,5 @@ func (self *Verifier) Clone(verifier *Verifier) {
 		verifier.M,
 		&verifier.Ck,
 		verifier.Com)
+	self.Workers = verifier.Workers
 }
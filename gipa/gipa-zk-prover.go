@@ -0,0 +1,62 @@
+package gipa
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// ZKProver is a struct to manage the prover state of the zero-knowledge mode.
+type ZKProver struct {
+	Prover Prover
+	Com    cm.Com // Hiding commitment to A, B and <A, B>
+	cm.Blinder
+}
+
+// Transform is a member function of ZKProver
+// It computes ComL and ComR as Prover.Transform and blinds them with fresh factors.
+func (self *ZKProver) Transform() (cm.Com, cm.Com) {
+	ComL, ComR := self.Prover.Transform()
+	self.Prover.ComL, self.Prover.ComR = self.Blinder.Blind(&ComL, &ComR)
+	return self.Prover.ComL, self.Prover.ComR
+}
+
+// Fold is a member function of ZKProver
+// It folds A, B, ck as Prover.Fold and the blinding factors as the commitments.
+func (self *ZKProver) Fold(x mcl.Fr) {
+	self.Prover.Fold(x)
+	self.Blinder.Fold(x)
+}
+
+func (self *ZKProver) Prove() ZKProof {
+	var proof ZKProof
+
+	m := self.Prover.M
+	self.Prover.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
+	i := 0
+	for m > 1 {
+		ComL, ComR := self.Transform()
+		proof.Append(ComL, ComR)
+		x := self.Prover.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.Prover.RandomChallenges[i] = x
+		i++
+	}
+	proof.Opening = cm.ProveOpening(&self.Prover.Ck, self.Prover.A[0], self.Prover.B[0], &self.U, self.Rho, &self.Prover.Transcript)
+	return proof
+}
+
+// Init computes the hiding commitment with the blinding factors rho, see cm.RandomBlinding.
+// The verifier has to be given Com.
+func (self *ZKProver) Init(M uint64, ck *cm.Ck, A []mcl.G1, B []mcl.G2, rho [3]mcl.Fr) {
+
+	*self = ZKProver{}
+	self.Prover.Init(M, ck, A, B)
+	self.Blinder = cm.NewBlinder(rho)
+	Z := utils.InnerProd(self.Prover.A, self.Prover.B)
+	self.Com = cm.IPPCMHiding(&self.Prover.Ck, self.Prover.A, self.Prover.B, Z, &self.U, rho)
+	self.Prover.Transcript = cm.HidingTranscript(zkLabel, &self.Com)
+}
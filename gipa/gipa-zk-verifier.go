@@ -0,0 +1,61 @@
+package gipa
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+)
+
+// ZKVerifier is a struct to manage the verifier state of the zero-knowledge mode.
+type ZKVerifier struct {
+	Verifier Verifier
+	U        mcl.GT // cm.HidingBase
+}
+
+// Reduce is a member function of ZKVerifier
+// It folds the commitment and ck with the round messages of the proof, see Verifier.Reduce.
+// Returns false if the proof has the wrong number of rounds.
+func (self *ZKVerifier) Reduce(proof ZKProof) bool {
+
+	m := self.Verifier.M
+	rounds := uint64(bits.Len64(m - 1))
+	if uint64(len(proof.L)) != rounds || uint64(len(proof.R)) != rounds {
+		return false
+	}
+	i := uint64(0)
+	for m > 1 {
+		self.Verifier.Transform()
+		self.Verifier.Update(proof.At(i))
+		x := self.Verifier.FiatShamir()
+		self.Verifier.Fold(x)
+		m = m / 2
+		i = i + 1
+	}
+	self.Verifier.FoldCom(proof.L, proof.R)
+	self.Verifier.FoldCk()
+	return true
+}
+
+func (self *ZKVerifier) Verify(proof ZKProof) bool {
+	if !self.Reduce(proof) {
+		return false
+	}
+	c := cm.OpeningChallenge(&self.Verifier.Transcript, &proof.Opening)
+	return self.CheckFinal(proof, c)
+}
+
+// CheckFinal is a member function of ZKVerifier
+// It checks the opening of the folded commitment for the challenge c. Call it after Reduce.
+func (self *ZKVerifier) CheckFinal(proof ZKProof, c mcl.Fr) bool {
+	return cm.CheckOpening(&self.Verifier.Ck, &self.U, &self.Verifier.Com, &proof.Opening, c)
+}
+
+// Init takes the hiding commitment com, see ZKProver.Init.
+func (self *ZKVerifier) Init(M uint64, ck *cm.Ck, com cm.Com) {
+
+	*self = ZKVerifier{}
+	self.Verifier.Init(M, ck, com)
+	self.U = cm.HidingBase()
+	self.Verifier.Transcript = cm.HidingTranscript(zkLabel, &com)
+}
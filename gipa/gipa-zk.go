@@ -0,0 +1,29 @@
+package gipa
+
+import (
+	"github.com/hyperproofs/gipa-go/cm"
+)
+
+// Zero-knowledge mode of GIPA.
+// The statement is a hiding commitment cm.IPPCMHiding(ck, A, B, <A, B>, u, rho).
+// Each round blinds ComL and ComR with fresh factors, which fold along with the commitment:
+//   rho' = x rho_L + rho + x^{-1} rho_R
+// Instead of A[0] and B[0], the prover sends a cm.Opening of the folded commitment.
+
+type ZKProof struct {
+	L       []cm.Com   // Blinded left commitments at each level
+	R       []cm.Com   // Blinded right commitments at each level
+	Opening cm.Opening // Opening of the folded commitment
+}
+
+// zkLabel separates the transcript of the zero-knowledge mode, see cm.HidingTranscript.
+const zkLabel = "GIPA ZK"
+
+func (self *ZKProof) Append(ComL cm.Com, ComR cm.Com) {
+	self.L = append(self.L, ComL)
+	self.R = append(self.R, ComR)
+}
+
+func (self *ZKProof) At(i uint64) (cm.Com, cm.Com) {
+	return self.L[i], self.R[i]
+}
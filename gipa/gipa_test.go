@@ -1,8 +1,15 @@
 package gipa
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
 	"github.com/hyperproofs/gipa-go/utils"
 )
 
@@ -17,3 +24,258 @@ func TestGIPA(t *testing.T) {
 		t.Errorf("GIPA Test: Failed")
 	}
 }
+
+func TestGIPAWorkers(t *testing.T) {
+
+	M := uint64(1) << 6
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaTestSetup(M, alpha, beta, g, h)
+	var parallel Prover
+	parallel.Clone(&prover)
+	parallel.Workers = 4
+
+	proof := prover.Prove()
+	proofParallel := parallel.Prove()
+	data, _ := proof.MarshalBinary()
+	dataParallel, _ := proofParallel.MarshalBinary()
+	if !bytes.Equal(data, dataParallel) {
+		t.Errorf("GIPA Workers: Parallel proof differs from the sequential proof")
+	}
+	verifier.Workers = 4
+	if !verifier.Verify(proofParallel) {
+		t.Errorf("GIPA Workers: Parallel proof did not verify")
+	}
+}
+
+func TestGIPAProveContext(t *testing.T) {
+
+	M := uint64(1) << 6
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaTestSetup(M, alpha, beta, g, h)
+	var local, cancelled Prover
+	local.Clone(&prover)
+	cancelled.Clone(&prover) // Prove consumes prover
+	var phases []utils.Progress
+	local.Progress = func(p utils.Progress) { phases = append(phases, p) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	proof, err := local.ProveContext(ctx)
+	if err != nil {
+		t.Fatalf("GIPA ProveContext: %v", err)
+	}
+	want := []utils.Phase{utils.PhaseTransform, utils.PhaseFiatShamir, utils.PhaseFold}
+	if len(phases) != len(want)*6 {
+		t.Fatalf("GIPA ProveContext: %d phases reported", len(phases))
+	}
+	for i, p := range phases[:len(want)*6] {
+		if p.Round != i/len(want) || p.M != M>>(i/len(want)) || p.Phase != want[i%len(want)] {
+			t.Errorf("GIPA ProveContext: Unexpected progress %d: %+v", i, p)
+		}
+	}
+	data, _ := proof.MarshalBinary()
+	proofProve := prover.Prove()
+	dataProve, _ := proofProve.MarshalBinary()
+	if !bytes.Equal(data, dataProve) {
+		t.Errorf("GIPA ProveContext: Proof differs from Prove")
+	}
+	if !verifier.Verify(proof) {
+		t.Errorf("GIPA ProveContext: Proof did not verify")
+	}
+
+	// Cancel once the first round is folded
+	cancelled.Progress = func(p utils.Progress) {
+		if p.Phase == utils.PhaseFold {
+			cancel()
+		}
+	}
+	if _, err := cancelled.ProveContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GIPA ProveContext: Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGIPABatchVerify(t *testing.T) {
+
+	M := uint64(1) << 4
+	n := 8
+	alpha, beta, g, h := utils.RunMPC()
+	ck := cm.IPPSetup(M, alpha, beta, g, h)
+	coms := make([]cm.Com, n)
+	proofs := make([]Proof, n)
+	var verifier Verifier
+	for i := 0; i < n; i++ {
+		A, B := utils.GenerateData(M)
+		var prover Prover
+		prover, verifier = AssembleProverVerifier(M, ck, A, B)
+		coms[i] = verifier.Com
+		proofs[i] = prover.Prove()
+	}
+
+	t.Run(fmt.Sprintf("%d/Batch;", n), func(t *testing.T) {
+		if status, i := verifier.BatchVerify(coms, proofs); !status || i != -1 {
+			t.Errorf("GIPA BatchVerify: Failed at %d", i)
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/BatchReject;", n), func(t *testing.T) {
+		tampered := make([]Proof, n)
+		copy(tampered, proofs)
+		mcl.G1Add(&tampered[5].A[0], &tampered[5].A[0], &g)
+		if status, i := verifier.BatchVerify(coms, tampered); status || i != 5 {
+			t.Errorf("GIPA BatchVerify: Tampered proof not pinpointed: %v %d", status, i)
+		}
+		tampered[5] = proofs[5]
+		tampered[2].L = tampered[2].L[1:]
+		tampered[2].R = tampered[2].R[1:]
+		if status, i := verifier.BatchVerify(coms, tampered); status || i != 2 {
+			t.Errorf("GIPA BatchVerify: Truncated proof not pinpointed: %v %d", status, i)
+		}
+	})
+}
+
+func TestGIPAEncoding(t *testing.T) {
+
+	M := uint64(1) << 4
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaTestSetup(M, alpha, beta, g, h)
+	proof := prover.Prove()
+
+	data, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatalf("GIPA Encoding: Marshal failed: %v", err)
+	}
+
+	t.Run(fmt.Sprintf("%d/RoundTrip;", M), func(t *testing.T) {
+		var decoded Proof
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("GIPA Encoding: Unmarshal failed: %v", err)
+		}
+		again, _ := decoded.MarshalBinary()
+		if !bytes.Equal(data, again) {
+			t.Errorf("GIPA Encoding: Round trip changed the bytes")
+		}
+		if !verifier.Verify(decoded) {
+			t.Errorf("GIPA Encoding: Decoded proof did not verify")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/Stream;", M), func(t *testing.T) {
+		var buf bytes.Buffer
+		proof.WriteTo(&buf)
+		proof.WriteTo(&buf)
+		var p1, p2 Proof
+		n, err := p1.ReadFrom(&buf)
+		if err != nil || n != int64(len(data)) {
+			t.Fatalf("GIPA Encoding: ReadFrom failed: %d %v", n, err)
+		}
+		if _, err := p2.ReadFrom(&buf); err != nil {
+			t.Fatalf("GIPA Encoding: ReadFrom failed on second proof: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("GIPA Encoding: %d bytes left in the stream", buf.Len())
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/Reject;", M), func(t *testing.T) {
+		var decoded Proof
+		for _, cut := range []int{0, utils.WireHeaderSize - 1, utils.WireHeaderSize, len(data) - 1} {
+			if err := decoded.UnmarshalBinary(data[:cut]); !errors.Is(err, utils.ErrTruncated) {
+				t.Errorf("GIPA Encoding: Truncation at %d not detected: %v", cut, err)
+			}
+		}
+		if err := decoded.UnmarshalBinary(append(data, 0)); !errors.Is(err, utils.ErrTrailingBytes) {
+			t.Errorf("GIPA Encoding: Trailing bytes not detected: %v", err)
+		}
+		bad := proof
+		bad.R = bad.R[1:]
+		if _, err := bad.MarshalBinary(); err == nil {
+			t.Errorf("GIPA Encoding: L/R mismatch not detected")
+		}
+	})
+}
+
+func TestGIPAJSON(t *testing.T) {
+
+	M := uint64(1) << 4
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaTestSetup(M, alpha, beta, g, h)
+	proof := prover.Prove()
+
+	data, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatalf("GIPA JSON: Marshal failed: %v", err)
+	}
+	var decoded Proof
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("GIPA JSON: Unmarshal failed: %v", err)
+	}
+
+	want, _ := proof.MarshalBinary()
+	got, _ := decoded.MarshalBinary()
+	if !bytes.Equal(want, got) {
+		t.Errorf("GIPA JSON: Binary form changed after JSON round trip")
+	}
+	if !verifier.Verify(decoded) {
+		t.Errorf("GIPA JSON: Decoded proof did not verify")
+	}
+
+	var generic map[string]interface{}
+	json.Unmarshal(data, &generic)
+	rounds, _ := generic["Rounds"].([]interface{})
+	if len(rounds) != len(proof.L) {
+		t.Errorf("GIPA JSON: Expected %d rounds, got %d", len(proof.L), len(rounds))
+	}
+}
+
+func TestGIPAZK(t *testing.T) {
+
+	M := uint64(1) << 4
+	alpha, beta, g, h := utils.RunMPC()
+	ck, A, B := GenerateGipaInstance(M, alpha, beta, g, h)
+
+	prover := ZKProver{}
+	prover.Init(M, ck, A, B, cm.RandomBlinding())
+	com := prover.Com
+	proof := prover.Prove()
+
+	t.Run(fmt.Sprintf("%d/ZK;", M), func(t *testing.T) {
+		verifier := ZKVerifier{}
+		verifier.Init(M, ck, com)
+		if !verifier.Verify(proof) {
+			t.Errorf("GIPA ZK Test: Failed")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/ZKReject;", M), func(t *testing.T) {
+		verifier := ZKVerifier{}
+		// The non-hiding commitment to the same A and B
+		verifier.Init(M, ck, cm.IPPCM(ck, A, B, utils.InnerProd(A, B)))
+		if verifier.Verify(proof) {
+			t.Errorf("GIPA ZK Test: Wrong commitment accepted")
+		}
+		tampered := proof
+		mcl.FrAdd(&tampered.Opening.Tau[2], &tampered.Opening.Tau[2], &alpha)
+		verifier.Init(M, ck, com)
+		if verifier.Verify(tampered) {
+			t.Errorf("GIPA ZK Test: Tampered opening accepted")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/ZKSimulator;", M), func(t *testing.T) {
+		// The simulator only gets the statement and chooses the final challenge.
+		simulated, c := SimulateZKProof(M, ck, com)
+		verifier := ZKVerifier{}
+		verifier.Init(M, ck, com)
+		if !verifier.Reduce(simulated) {
+			t.Fatalf("GIPA ZK Test: Simulated proof has the wrong shape")
+		}
+		if !verifier.CheckFinal(simulated, c) {
+			t.Errorf("GIPA ZK Test: Simulated transcript rejected")
+		}
+		// Without programming the hash, the simulated proof does not verify.
+		verifier.Init(M, ck, com)
+		if verifier.Verify(simulated) {
+			t.Errorf("GIPA ZK Test: Simulated proof accepted under Fiat-Shamir")
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package gipa
 
 import (
+	"math/bits"
+
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/cm"
 	"github.com/hyperproofs/gipa-go/utils"
@@ -33,3 +35,26 @@ func GipaTestSetup(m uint64, alpha mcl.Fr, beta mcl.Fr, g mcl.G1, h mcl.G2) (Pro
 	prover, verifier := AssembleProverVerifier(m, ck, A, B)
 	return prover, verifier
 }
+
+// SimulateZKProof returns a proof for com and the challenge of its final opening, without knowing A, B or rho.
+// The round messages are random powers of u, like the blinded messages of ZKProver.
+// The opening is accepted by cm.CheckOpening for the returned challenge.
+func SimulateZKProof(m uint64, ck *cm.Ck, com cm.Com) (ZKProof, mcl.Fr) {
+	var proof ZKProof
+	u := cm.HidingBase()
+	var one cm.Com
+	for i := range one.Com {
+		one.Com[i].SetInt64(1)
+	}
+	for i := 0; i < bits.Len64(m-1); i++ {
+		proof.Append(cm.ComBlind(&one, &u, cm.RandomBlinding()), cm.ComBlind(&one, &u, cm.RandomBlinding()))
+	}
+
+	verifier := ZKVerifier{}
+	verifier.Init(m, ck, com)
+	verifier.Reduce(proof)
+	var c mcl.Fr
+	c.Random()
+	proof.Opening = cm.SimulateOpening(&verifier.Verifier.Ck, &u, &verifier.Verifier.Com, c)
+	return proof, c
+}
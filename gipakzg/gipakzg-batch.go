@@ -0,0 +1,52 @@
+package gipakzg
+
+import (
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// BatchVerify is a member function of Verifier
+// It checks proofs[i] against the statement coms[i], with the keys, M and VScale of the verifier.
+// Each transcript is replayed as in Verify. The PairingTerms of the proofs are merged into a single multi-pairing.
+// The KZG openings share the keys, thus they only add four pairings for the whole batch.
+// The verifiers of the proofs share self.Prepared.
+// Parameters
+// ----------
+// coms, slice of cm.Com, one statement per proof
+// proofs, slice of Proof
+//
+// Returns
+// -------
+// bool, true if all the proofs verify
+// int, index of the first proof which fails, -1 if all the proofs verify, or if the batch failed
+// but the culprit is not located (see utils.LocateFailure)
+func (self *Verifier) BatchVerify(coms []cm.Com, proofs []Proof) (bool, int) {
+
+	utils.SizeMismatchCheck(uint64(len(coms)), uint64(len(proofs)), "GIPA KZG BatchVerify: Proofs Size:")
+
+	n := len(proofs)
+	if n == 0 {
+		return true, -1
+	}
+	verifiers := make([]Verifier, n)
+	terms := make([]PairingTerms, n)
+	total := NewPairingTerms()
+	prepared := self.prepared()
+	for i := range proofs {
+		verifiers[i].InitPrepared(self.M, &self.KZG1, &self.KZG2, coms[i], prepared)
+		verifiers[i].VScale = self.VScale
+		if !verifiers[i].Reduce(proofs[i]) {
+			return false, i
+		}
+		terms[i] = verifiers[i].Terms(&proofs[i])
+		total.Merge(&terms[i])
+	}
+	if self.CheckTerms(&total) {
+		return true, -1
+	}
+
+	// Find the culprit. The transcripts are already replayed.
+	return utils.LocateFailure(n, func(i int) bool {
+		return self.CheckTerms(&terms[i])
+	})
+}
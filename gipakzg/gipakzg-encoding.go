@@ -0,0 +1,128 @@
+package gipakzg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// Binary encoding of the GIPA+KZG proof
+// header (utils.WriteWireHeader) | L[0..rounds) | R[0..rounds) | A[0] | B[0] | W | V | Pi1 | Pi2
+
+// MarshalBinary encodes the proof along with the wire header.
+func (self *Proof) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := self.encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a proof written by MarshalBinary.
+// It rejects truncated input and trailing bytes.
+func (self *Proof) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if err := self.decode(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("GIPA KZG Proof: %w: %d bytes", utils.ErrTrailingBytes, r.Len())
+	}
+	return nil
+}
+
+// WriteTo writes the encoded proof to w.
+func (self *Proof) WriteTo(w io.Writer) (int64, error) {
+	data, err := self.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads exactly one encoded proof from r.
+// Unlike UnmarshalBinary, it does not look past the end of the proof. Thus, proofs can be streamed back to back.
+func (self *Proof) ReadFrom(r io.Reader) (int64, error) {
+	cr := utils.CountingReader{R: r}
+	err := self.decode(&cr)
+	return cr.N, err
+}
+
+func (self *Proof) encode(w io.Writer) error {
+
+	if len(self.L) != len(self.R) {
+		return fmt.Errorf("GIPA KZG Proof: L and R size mismatch: %d %d", len(self.L), len(self.R))
+	}
+	if err := utils.WriteWireHeader(w, utils.ProofTypeGipaKzg, len(self.L)); err != nil {
+		return err
+	}
+	for i := range self.L {
+		if err := cm.WriteCom(w, &self.L[i]); err != nil {
+			return err
+		}
+	}
+	for i := range self.R {
+		if err := cm.WriteCom(w, &self.R[i]); err != nil {
+			return err
+		}
+	}
+	if err := utils.WriteG1(w, &self.A[0]); err != nil {
+		return err
+	}
+	if err := utils.WriteG2(w, &self.B[0]); err != nil {
+		return err
+	}
+	if err := utils.WriteG1(w, &self.W); err != nil {
+		return err
+	}
+	if err := utils.WriteG2(w, &self.V); err != nil {
+		return err
+	}
+	if err := utils.WriteG1(w, &self.Pi1); err != nil {
+		return err
+	}
+	return utils.WriteG2(w, &self.Pi2)
+}
+
+func (self *Proof) decode(r io.Reader) error {
+
+	rounds, err := utils.ReadWireHeader(r, utils.ProofTypeGipaKzg)
+	if err != nil {
+		return fmt.Errorf("GIPA KZG Proof: %w", err)
+	}
+	proof := Proof{L: make([]cm.Com, rounds), R: make([]cm.Com, rounds)}
+	for i := range proof.L {
+		if err := cm.ReadCom(r, &proof.L[i]); err != nil {
+			return fmt.Errorf("GIPA KZG Proof: L[%d]: %w", i, err)
+		}
+	}
+	for i := range proof.R {
+		if err := cm.ReadCom(r, &proof.R[i]); err != nil {
+			return fmt.Errorf("GIPA KZG Proof: R[%d]: %w", i, err)
+		}
+	}
+	if err := utils.ReadG1(r, &proof.A[0]); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: A: %w", err)
+	}
+	if err := utils.ReadG2(r, &proof.B[0]); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: B: %w", err)
+	}
+	if err := utils.ReadG1(r, &proof.W); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: W: %w", err)
+	}
+	if err := utils.ReadG2(r, &proof.V); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: V: %w", err)
+	}
+	if err := utils.ReadG1(r, &proof.Pi1); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: Pi1: %w", err)
+	}
+	if err := utils.ReadG2(r, &proof.Pi2); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: Pi2: %w", err)
+	}
+	*self = proof
+	return nil
+}
@@ -0,0 +1,74 @@
+package gipakzg
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+type proofJSON struct {
+	utils.WireHeaderJSON
+	Rounds []cm.ComRoundJSON `json:"Rounds"`
+	A      string            `json:"A"`
+	B      string            `json:"B"`
+	W      string            `json:"W"`
+	V      string            `json:"V"`
+	Pi1    string            `json:"Pi1"`
+	Pi2    string            `json:"Pi2"`
+}
+
+// MarshalJSON encodes the proof with hex group elements and one entry per round.
+func (self Proof) MarshalJSON() ([]byte, error) {
+	rounds, err := cm.RoundsToJSON(self.L, self.R)
+	if err != nil {
+		return nil, fmt.Errorf("GIPA KZG Proof: %w", err)
+	}
+	out := proofJSON{
+		utils.NewWireHeaderJSON(utils.ProofTypeGipaKzg),
+		rounds,
+		utils.G1ToHex(&self.A[0]),
+		utils.G2ToHex(&self.B[0]),
+		utils.G1ToHex(&self.W),
+		utils.G2ToHex(&self.V),
+		utils.G1ToHex(&self.Pi1),
+		utils.G2ToHex(&self.Pi2),
+	}
+	return json.Marshal(out)
+}
+
+func (self *Proof) UnmarshalJSON(data []byte) error {
+	var in proofJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: %w", err)
+	}
+	if err := in.Check(utils.ProofTypeGipaKzg); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: %w", err)
+	}
+	L, R, err := cm.RoundsFromJSON(in.Rounds)
+	if err != nil {
+		return fmt.Errorf("GIPA KZG Proof: %w", err)
+	}
+	proof := Proof{L: L, R: R}
+	if err := utils.G1FromHex(&proof.A[0], in.A); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: A: %w", err)
+	}
+	if err := utils.G2FromHex(&proof.B[0], in.B); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: B: %w", err)
+	}
+	if err := utils.G1FromHex(&proof.W, in.W); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: W: %w", err)
+	}
+	if err := utils.G2FromHex(&proof.V, in.V); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: V: %w", err)
+	}
+	if err := utils.G1FromHex(&proof.Pi1, in.Pi1); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: Pi1: %w", err)
+	}
+	if err := utils.G2FromHex(&proof.Pi2, in.Pi2); err != nil {
+		return fmt.Errorf("GIPA KZG Proof: Pi2: %w", err)
+	}
+	*self = proof
+	return nil
+}
@@ -0,0 +1,126 @@
+package gipakzg
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// PairingTerms holds the final pairing equations of one or more proofs, combined with random coefficients.
+// The proofs are valid (w.h.p.) iff
+// prod e(P_i, Q_i) * e(KZG1, h) * e(-Pi1, h^alpha) * e(g, KZG2) * e(-g^beta, Pi2) == T
+// where h, h^alpha are KZG1.VK and g, g^beta are KZG2.VK.
+// The KZG openings share the keys, thus merging terms only adds pairings for the commitment checks.
+type PairingTerms struct {
+	T    mcl.GT   // prod Com_k^{c_k} of the folded commitments
+	P    []mcl.G1 // Commitment checks
+	Q    []mcl.G2
+	KZG1 mcl.G1 // sum d (W + a Pi1 - yw g)
+	Pi1  mcl.G1 // sum d Pi1
+	KZG2 mcl.G2 // sum e (V + b Pi2 - yv h)
+	Pi2  mcl.G2 // sum e Pi2
+}
+
+// Merge is a member function of PairingTerms
+// It adds the equations of terms to self. The zero value of PairingTerms is not a valid accumulator,
+// use NewPairingTerms.
+func (self *PairingTerms) Merge(terms *PairingTerms) {
+	mcl.GTMul(&self.T, &self.T, &terms.T)
+	self.P = append(self.P, terms.P...)
+	self.Q = append(self.Q, terms.Q...)
+	mcl.G1Add(&self.KZG1, &self.KZG1, &terms.KZG1)
+	mcl.G1Add(&self.Pi1, &self.Pi1, &terms.Pi1)
+	mcl.G2Add(&self.KZG2, &self.KZG2, &terms.KZG2)
+	mcl.G2Add(&self.Pi2, &self.Pi2, &terms.Pi2)
+}
+
+// NewPairingTerms returns the empty set of equations, to be filled with Merge.
+func NewPairingTerms() PairingTerms {
+	terms := PairingTerms{}
+	terms.T.SetInt64(1)
+	return terms
+}
+
+// Terms is a member function of Verifier
+// It returns the final pairing equations of proof: the checks of the folded com (see Check)
+// and the KZG openings of W and V (see CheckKeys), each scaled by a random coefficient.
+// Call it after Reduce. It updates the transcript.
+func (self *Verifier) Terms(proof *Proof) PairingTerms {
+
+	a, b, yw, yv := self.keyChallenges(&proof.A[0], &proof.B[0], &proof.Pi1)
+	terms := NewPairingTerms()
+
+	// c_0 <A, V> + c_2 e(A, B) = e(A, c_0 V + c_2 B) and c_1 <W, B> = e(c_1 W, B)
+	var c [3]mcl.Fr
+	var temp mcl.GT
+	for k := range c {
+		c[k].Random()
+		mcl.GTPow(&temp, &self.Com.Com[k], &c[k])
+		mcl.GTMul(&terms.T, &terms.T, &temp)
+	}
+	var cW mcl.G1
+	var cV, cB mcl.G2
+	mcl.G2Mul(&cV, &proof.V, &c[0])
+	mcl.G2Mul(&cB, &proof.B[0], &c[2])
+	mcl.G2Add(&cV, &cV, &cB)
+	mcl.G1Mul(&cW, &proof.W, &c[1])
+	terms.P = []mcl.G1{proof.A[0], cW}
+	terms.Q = []mcl.G2{cV, proof.B[0]}
+
+	// d (W + a Pi1 - yw g) and d Pi1
+	var d mcl.Fr
+	var g1Tmp mcl.G1
+	d.Random()
+	mcl.G1Mul(&g1Tmp, &proof.Pi1, &a)
+	mcl.G1Add(&terms.KZG1, &proof.W, &g1Tmp)
+	mcl.G1Mul(&g1Tmp, &self.KZG1.PK[0], &yw)
+	mcl.G1Sub(&terms.KZG1, &terms.KZG1, &g1Tmp)
+	mcl.G1Mul(&terms.KZG1, &terms.KZG1, &d)
+	mcl.G1Mul(&terms.Pi1, &proof.Pi1, &d)
+
+	// e (V + b Pi2 - yv h) and e Pi2
+	var e mcl.Fr
+	var g2Tmp mcl.G2
+	e.Random()
+	mcl.G2Mul(&g2Tmp, &proof.Pi2, &b)
+	mcl.G2Add(&terms.KZG2, &proof.V, &g2Tmp)
+	mcl.G2Mul(&g2Tmp, &self.KZG2.PK[0], &yv)
+	mcl.G2Sub(&terms.KZG2, &terms.KZG2, &g2Tmp)
+	mcl.G2Mul(&terms.KZG2, &terms.KZG2, &e)
+	mcl.G2Mul(&terms.Pi2, &proof.Pi2, &e)
+
+	return terms
+}
+
+// CheckTerms is a member function of Verifier
+// It checks terms with one multi-pairing and a single final exponentiation.
+// The pairings with h and h^alpha use the precomputed lines of self.Prepared.
+func (self *Verifier) CheckTerms(terms *PairingTerms) bool {
+
+	n := len(terms.P)
+	P := make([]mcl.G1, n, n+2)
+	Q := make([]mcl.G2, n, n+2)
+	copy(P, terms.P)
+	copy(Q, terms.Q)
+
+	var negPi1 mcl.G1
+	var negPi2 mcl.G2
+	mcl.G1Neg(&negPi1, &terms.Pi1)
+	mcl.G2Neg(&negPi2, &terms.Pi2)
+	P = append(P, self.KZG2.VK[0], self.KZG2.VK[1])
+	Q = append(Q, terms.KZG2, negPi2)
+
+	var result, prepared mcl.GT
+	mcl.MillerLoopVec(&result, P, Q)
+	self.prepared().MillerLoop(&prepared, []mcl.G1{terms.KZG1, negPi1})
+	mcl.GTMul(&result, &result, &prepared)
+	mcl.FinalExp(&result, &result)
+	return result.IsEqual(&terms.T)
+}
+
+// prepared returns self.Prepared, and creates it on first use.
+func (self *Verifier) prepared() *utils.PreparedVerifyingKey {
+	if self.Prepared == nil {
+		self.Prepared = utils.NewPreparedVerifyingKey(self.KZG1.VK)
+	}
+	return self.Prepared
+}
@@ -1,7 +1,9 @@
 package gipakzg
 
 import (
+	"context"
 	"math/bits"
+	"time"
 
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/cm"
@@ -36,6 +38,17 @@ type Prover struct {
 
 	Transcript       [32]byte
 	RandomChallenges []mcl.Fr
+
+	// Workers is the number of goroutines used in each round. 1 runs sequentially.
+	// 0 picks it from the size of the round, see utils.InnerProd and utils.G1FoldInto.
+	// The proof does not depend on it.
+	Workers int
+
+	// Progress, if not nil, is called after each phase of ProveContext and Prove.
+	Progress utils.ProgressFunc
+
+	// VScale is s if Ck.V holds V_i^{s^i}, as in SnarkPack. Zero means no rescaling.
+	VScale mcl.Fr
 }
 
 // Transform is a member function of Prover
@@ -54,6 +67,13 @@ type Prover struct {
 // Updates the data members ComL and ComR.
 // Returns ComL, ComR so that verifier can pose the challenge
 func (self *Prover) Transform() (cm.Com, cm.Com) {
+	ComL, ComR, _ := self.TransformContext(context.Background())
+	return ComL, ComR
+}
+
+// TransformContext is Transform, which stops the pairing products once ctx is done, see utils.InnerProdsContext.
+// On error, the prover state is left half transformed.
+func (self *Prover) TransformContext(ctx context.Context) (cm.Com, cm.Com, error) {
 	MPrime := self.M / 2
 	self.MPrime = MPrime
 	self.A_L = self.A[:MPrime]
@@ -61,14 +81,23 @@ func (self *Prover) Transform() (cm.Com, cm.Com) {
 	self.B_L = self.B[:MPrime]
 	self.B_R = self.B[MPrime:]
 
-	self.Z_L = utils.InnerProd(self.A_R, self.B_L)
-	self.Z_R = utils.InnerProd(self.A_L, self.B_R)
-
 	self.Ck.Transform(&self.Ck1, &self.Ck2)
-	self.ComL = cm.IPPCM(&self.Ck1, self.A_R, self.B_L, self.Z_L)
-	self.ComR = cm.IPPCM(&self.Ck2, self.A_L, self.B_R, self.Z_R)
 
-	return self.ComL, self.ComR
+	// The six pairing products are independent: Z_L, Z_R, then (<A, V>, <W, B>) of ComL and ComR
+	prods, err := utils.InnerProdsContext(ctx,
+		[][]mcl.G1{self.A_R, self.A_L, self.A_R, self.Ck1.W, self.A_L, self.Ck2.W},
+		[][]mcl.G2{self.B_L, self.B_R, self.Ck1.V, self.B_L, self.Ck2.V, self.B_R},
+		self.Workers,
+	)
+	if err != nil {
+		return cm.Com{}, cm.Com{}, err
+	}
+	self.Z_L = prods[0]
+	self.Z_R = prods[1]
+	self.ComL = cm.Com{Com: [3]mcl.GT{prods[2], prods[3], self.Z_L}}
+	self.ComR = cm.Com{Com: [3]mcl.GT{prods[4], prods[5], self.Z_R}}
+
+	return self.ComL, self.ComR, nil
 }
 
 // Fold is a member function of Prover
@@ -89,10 +118,13 @@ func (self *Prover) Fold(x mcl.Fr) {
 	mcl.FrInv(&y, &x)
 
 	self.X = append(self.X, x)
-	self.A = utils.G1Fold(x, self.A_R, self.A_L)
-	self.B = utils.G2Fold(y, self.B_R, self.B_L)
+	// In place: the folded vectors overwrite the left halves
+	utils.G1FoldInto(self.A_L, x, self.A_R, self.A_L, self.Workers)
+	utils.G2FoldInto(self.B_L, y, self.B_R, self.B_L, self.Workers)
+	self.A = self.A_L
+	self.B = self.B_L
 
-	cm.CkFold(&self.Ck, x, y, &self.Ck)
+	cm.CkFoldInPlace(&self.Ck, x, y, self.Workers)
 	self.M = self.MPrime
 }
 
@@ -123,19 +155,38 @@ func (self *Prover) FiatShamir() mcl.Fr {
 // }
 
 func (self *Prover) Prove() Proof {
+	proof, _ := self.ProveContext(context.Background())
+	return proof
+}
+
+// ProveContext is Prove, which returns ctx.Err() once ctx is done.
+// ctx is checked between the phases of each round and inside the pairing products of Transform.
+// The prover state is consumed either way, use Clone to prove again.
+func (self *Prover) ProveContext(ctx context.Context) (Proof, error) {
 	var proof Proof
-	// proofSize := uint32(math.Ceil(math.Log2(float64(self.m)))) + 1
-	// x.New(proofSize)
 
-	// fmt.Println("KZG Prover ZERO 1:", self.A[5].IsZero(), self.B[5].IsZero())
 	m := self.M
 	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
 	i := 0
 	for m > 1 {
-		ComL, ComR := self.Transform()
+		if err := ctx.Err(); err != nil {
+			return Proof{}, err
+		}
+		start := time.Now()
+		ComL, ComR, err := self.TransformContext(ctx)
+		if err != nil {
+			return Proof{}, err
+		}
+		self.Progress.Report(i, m, utils.PhaseTransform, start)
 		proof.Append(ComL, ComR)
+
+		start = time.Now()
 		x := self.FiatShamir()
+		self.Progress.Report(i, m, utils.PhaseFiatShamir, start)
+
+		start = time.Now()
 		self.Fold(x)
+		self.Progress.Report(i, m, utils.PhaseFold, start)
 		m = m / 2
 		self.RandomChallenges[i] = x
 		i++
@@ -143,45 +194,65 @@ func (self *Prover) Prove() Proof {
 	proof.A[0] = self.A[0]
 	proof.B[0] = self.B[0]
 
-	// fmt.Println("KZG Prover ZERO:", self.A[0].IsZero(), self.B[0].IsZero()) // REMOVE
+	if err := ctx.Err(); err != nil {
+		return Proof{}, err
+	}
+	start := time.Now()
+	proof.W, proof.Pi1, proof.V, proof.Pi2 = self.OpenKeys(&proof.A[0], &proof.B[0])
+	self.Progress.Report(i, 1, utils.PhaseOpening, start)
+	return proof, nil
+}
+
+// OpenKeys is a member function of Prover
+// It computes the final commitment keys W and V with the KZG proofs of the halo polynomials.
+// The first evaluation point is Hash(transcript || A || B), the second one is Hash(transcript || Pi1).
+// Parameters
+// ----------
+// A, B the values which the final keys are bound to
+//
+// Returns
+// -------
+// W, Pi1, V, Pi2
+func (self *Prover) OpenKeys(A *mcl.G1, B *mcl.G2) (mcl.G1, mcl.G1, mcl.G2, mcl.G2) {
 	// Hash(transcript || A || B)
+	var W, Pi1 mcl.G1
+	var V, Pi2 mcl.G2
 	var a, b mcl.Fr
 	data := make([]byte, 0)
 	data = append(data, self.Transcript[:]...)
-	data = append(data, proof.A[0].Serialize()...)
-	data = append(data, proof.B[0].Serialize()...)
+	data = append(data, A.Serialize()...)
+	data = append(data, B.Serialize()...)
 	hash := blake2b.Sum256(data)
 	copy(self.Transcript[:], hash[:])
 	a.SetHashOf(hash[:])
-	// fmt.Println("Hash for KZG open", hash) // REMOVE
 	fw := BuildHaloPoly(self.RandomChallenges, false)
-	proof.W = *self.KZG1.CommitToPoly(fw)
-	Pi1, _ := self.KZG1.ComputeProofSingle(fw, &a)
-	proof.Pi1 = *Pi1
-	if !proof.W.IsEqual(&self.Ck.W[0]) {
+	W = *self.KZG1.CommitToPoly(fw)
+	pi1, _ := self.KZG1.ComputeProofSingle(fw, &a)
+	Pi1 = *pi1
+	if !W.IsEqual(&self.Ck.W[0]) {
 		panic("GIPA KZG Prover: W Commitment key computed using GIPA does not match with HaloPoly evaluation.")
 	}
-	// fmt.Println("Check W", proof.W.IsEqual(&self.Ck.W[0]), proof.Pi1.IsZero(), self.Ck.W[0].IsZero())  // REMOVE
 
-	// Hash(transcript || proof.W)
+	// Hash(transcript || W)
 	data = make([]byte, 0)
 	data = append(data, self.Transcript[:]...)
-	data = append(data, proof.Pi1.Serialize()...)
+	data = append(data, Pi1.Serialize()...)
 	hash = blake2b.Sum256(data)
 	copy(self.Transcript[:], hash[:])
 	b.SetHashOf(hash[:])
-	// fmt.Println("Hash for KZG open2", hash) // REMOVE
-	fv := BuildHaloPoly(self.RandomChallenges, true)
-	proof.V = *self.KZG2.CommitToPoly(fv)
-	Pi2, _ := self.KZG2.ComputeProofSingle(fv, &b)
-	proof.Pi2 = *Pi2
-
-	// fmt.Println(self.RandomChallenges) // REMOVE
-	if !proof.V.IsEqual(&self.Ck.V[0]) {
+	vChallenges := self.RandomChallenges
+	if !self.VScale.IsZero() {
+		vChallenges = ScaleChallenges(self.RandomChallenges, self.VScale)
+	}
+	fv := BuildHaloPoly(vChallenges, true)
+	V = *self.KZG2.CommitToPoly(fv)
+	pi2, _ := self.KZG2.ComputeProofSingle(fv, &b)
+	Pi2 = *pi2
+
+	if !V.IsEqual(&self.Ck.V[0]) {
 		panic("GIPA KZG Prover: V Commitment key computed using GIPA does not match with HaloPoly evaluation.")
 	}
-	// fmt.Println("Check V", proof.V.IsEqual(&self.Ck.V[0]), proof.Pi2.IsZero(), self.Ck.V[0].IsZero()) // REMOVE
-	return proof
+	return W, Pi1, V, Pi2
 }
 
 // func (self *Prover) Print() {
@@ -224,4 +295,7 @@ func (self *Prover) Clone(prover *Prover) {
 		prover.A,
 		prover.B,
 	)
+	self.VScale = prover.VScale
+	self.Workers = prover.Workers
+	self.Progress = prover.Progress
 }
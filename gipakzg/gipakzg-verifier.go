@@ -25,8 +25,15 @@ type Verifier struct {
 	KZG1 kzg.KZG1Settings
 	KZG2 kzg.KZG2Settings
 
+	// Prepared holds KZG1.VK with its Miller-loop lines, see CheckTerms. It is created on first use,
+	// unless it is given to InitPrepared. Clone shares it.
+	Prepared *utils.PreparedVerifyingKey
+
 	Transcript       [32]byte
 	RandomChallenges []mcl.Fr
+
+	// VScale is s if the prover used V_i^{s^i}, see Prover.VScale. Zero means no rescaling.
+	VScale mcl.Fr
 }
 
 // Transform is a member function of Verifier
@@ -45,8 +52,7 @@ func (self *Verifier) Transform() {
 }
 
 // Fold is a member function of Verifier
-// Verifier computes the ck' and com'
-// Inner product of A', B' (prover state) should be equal to com'
+// Verifier records the challenge. com is folded at once by FoldCom.
 // Parameters
 // ----------
 // x, Fr challenge posed to the prover
@@ -54,16 +60,19 @@ func (self *Verifier) Transform() {
 // Returns
 // -------
 // None
-// Updates the data members, com and ck
+// Updates the data members, M and X
 func (self *Verifier) Fold(x mcl.Fr) {
 
-	var y mcl.Fr
-	mcl.FrInv(&y, &x)
-
 	self.M = self.MPrime
 	self.X = append(self.X, x)
+}
 
-	self.Com = cm.ComFold(x, y, &self.ComL, &self.Com, &self.ComR)
+// FoldCom is a member function of Verifier
+// It folds com with the left and right commitments of all the rounds, see cm.ComFoldAll.
+// Inner product of A', B' (prover state) should be equal to com'
+// Call it once all the challenges are in X.
+func (self *Verifier) FoldCom(L []cm.Com, R []cm.Com) {
+	self.Com = cm.ComFoldAll(&self.Com, L, R, self.X)
 }
 
 func (self *Verifier) FiatShamir() mcl.Fr {
@@ -91,8 +100,26 @@ func (self *Verifier) FiatShamir() mcl.Fr {
 
 func (self *Verifier) Verify(proof Proof) bool {
 
+	if !self.Reduce(proof) {
+		return false
+	}
+
+	// The checks of Check and CheckKeys as a single multi-pairing
+	terms := self.Terms(&proof)
+	return self.CheckTerms(&terms)
+}
+
+// Reduce is a member function of Verifier
+// It replays the transcript of the proof, records the challenges and folds com, see FoldCom.
+// Returns false if the proof has the wrong number of rounds.
+func (self *Verifier) Reduce(proof Proof) bool {
+
 	m := self.M
-	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
+	rounds := bits.Len64(m - 1)
+	if len(proof.L) != rounds || len(proof.R) != rounds {
+		return false
+	}
+	self.RandomChallenges = make([]mcl.Fr, rounds)
 	i := uint64(0)
 	for m > 1 {
 		self.Transform()
@@ -104,33 +131,46 @@ func (self *Verifier) Verify(proof Proof) bool {
 		self.RandomChallenges[i] = x
 		i = i + 1
 	}
+	self.FoldCom(proof.L, proof.R)
+	return true
+}
+
+// CheckKeys is a member function of Verifier
+// It checks the KZG proofs of the final commitment keys W and V, see Prover.OpenKeys.
+// It does not check that W and V open the folded commitment.
+func (self *Verifier) CheckKeys(A *mcl.G1, B *mcl.G2, W *mcl.G1, Pi1 *mcl.G1, V *mcl.G2, Pi2 *mcl.G2) bool {
+	a, b, yw, yv := self.keyChallenges(A, B, Pi1)
+	status := self.KZG1.CheckProofSingle(W, Pi1, &a, &yw)
+	status = status && self.KZG2.CheckProofSingle(V, Pi2, &b, &yv)
+	return status
+}
 
-	var status bool
-	status = true
-	status = status && self.Check(proof.A[:1], proof.B[:1], proof.W, proof.V)
+// keyChallenges returns the points a, b at which W and V are opened, and the expected evaluations yw, yv.
+// It updates the transcript.
+func (self *Verifier) keyChallenges(A *mcl.G1, B *mcl.G2, Pi1 *mcl.G1) (a, b, yw, yv mcl.Fr) {
 	// // Hash(transcript || A || B)
-	var a, b mcl.Fr
 	data := make([]byte, 0)
 	data = append(data, self.Transcript[:]...)
-	data = append(data, proof.A[0].Serialize()...)
-	data = append(data, proof.B[0].Serialize()...)
+	data = append(data, A.Serialize()...)
+	data = append(data, B.Serialize()...)
 	hash := blake2b.Sum256(data)
 	copy(self.Transcript[:], hash[:])
 	a.SetHashOf(hash[:])
 
 	data = make([]byte, 0)
 	data = append(data, self.Transcript[:]...)
-	data = append(data, proof.Pi1.Serialize()...)
+	data = append(data, Pi1.Serialize()...)
 	hash = blake2b.Sum256(data)
 	copy(self.Transcript[:], hash[:])
 	b.SetHashOf(hash[:])
 
-	yw := EvaluateHaloPoly(self.RandomChallenges, a, false)
-	yv := EvaluateHaloPoly(self.RandomChallenges, b, true)
-
-	status = status && self.KZG1.CheckProofSingle(&proof.W, &proof.Pi1, &a, &yw)
-	status = status && self.KZG2.CheckProofSingle(&proof.V, &proof.Pi2, &b, &yv)
-	return status
+	yw = EvaluateHaloPoly(self.RandomChallenges, a, false)
+	vChallenges := self.RandomChallenges
+	if !self.VScale.IsZero() {
+		vChallenges = ScaleChallenges(self.RandomChallenges, self.VScale)
+	}
+	yv = EvaluateHaloPoly(vChallenges, b, true)
+	return a, b, yw, yv
 }
 
 func (self *Verifier) Update(ComL cm.Com, ComR cm.Com) {
@@ -196,11 +236,25 @@ func (self *Verifier) Init(M uint64, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Setti
 	self.Com = com
 }
 
+// InitPrepared is Init with a PreparedVerifyingKey of kzg1.VK, which may be shared by many verifiers.
+// A nil prepared is created on first use, as in Init.
+func (self *Verifier) InitPrepared(M uint64, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, com cm.Com,
+	prepared *utils.PreparedVerifyingKey) {
+
+	if prepared != nil && !prepared.Matches(kzg1.VK) {
+		panic("GIPA KZG Verifier InitPrepared: Prepared key differs from KZG1 VK")
+	}
+	self.Init(M, kzg1, kzg2, com)
+	self.Prepared = prepared
+}
+
 func (self *Verifier) Clone(verifier *Verifier) {
-	self.Init(
+	self.InitPrepared(
 		verifier.M,
 		&verifier.KZG1,
 		&verifier.KZG2,
 		verifier.Com,
+		verifier.Prepared,
 	)
+	self.VScale = verifier.VScale
 }
@@ -0,0 +1,68 @@
+package gipakzg
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// ZKVerifier is a struct to manage the verifier state of the zero-knowledge mode.
+type ZKVerifier struct {
+	Verifier Verifier
+	U        mcl.GT // cm.HidingBase
+}
+
+// Reduce is a member function of ZKVerifier
+// It folds the commitment with the round messages of the proof and records the challenges.
+// Returns false if the proof has the wrong number of rounds.
+func (self *ZKVerifier) Reduce(proof ZKProof) bool {
+
+	m := self.Verifier.M
+	rounds := bits.Len64(m - 1)
+	if len(proof.L) != rounds || len(proof.R) != rounds {
+		return false
+	}
+	self.Verifier.RandomChallenges = make([]mcl.Fr, rounds)
+	i := uint64(0)
+	for m > 1 {
+		self.Verifier.Transform()
+		self.Verifier.Update(proof.At(i))
+		x := self.Verifier.FiatShamir()
+		self.Verifier.Fold(x)
+		m = m / 2
+		self.Verifier.RandomChallenges[i] = x
+		i = i + 1
+	}
+	self.Verifier.FoldCom(proof.L, proof.R)
+	return true
+}
+
+func (self *ZKVerifier) Verify(proof ZKProof) bool {
+	if !self.Reduce(proof) {
+		return false
+	}
+	c := cm.OpeningChallenge(&self.Verifier.Transcript, &proof.Opening)
+	return self.CheckFinal(proof, c)
+}
+
+// CheckFinal is a member function of ZKVerifier
+// It checks the opening of the folded commitment for the challenge c and the final keys.
+// Call it after Reduce, once the opening is in the transcript.
+func (self *ZKVerifier) CheckFinal(proof ZKProof, c mcl.Fr) bool {
+	ck := cm.Ck{M: 1, V: []mcl.G2{proof.V}, W: []mcl.G1{proof.W}} // Prover gives W and V
+	if !cm.CheckOpening(&ck, &self.U, &self.Verifier.Com, &proof.Opening, c) {
+		return false
+	}
+	return self.Verifier.CheckKeys(&proof.Opening.A, &proof.Opening.B, &proof.W, &proof.Pi1, &proof.V, &proof.Pi2)
+}
+
+// Init takes the hiding commitment com, see ZKProver.Init.
+func (self *ZKVerifier) Init(M uint64, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, com cm.Com) {
+
+	*self = ZKVerifier{}
+	self.Verifier.Init(M, kzg1, kzg2, com)
+	self.U = cm.HidingBase()
+	self.Verifier.Transcript = cm.HidingTranscript(zkLabel, &com)
+}
@@ -0,0 +1,32 @@
+package gipakzg
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+)
+
+// Zero-knowledge mode of GIPA with KZG, see the zero-knowledge mode of package gipa.
+// The final keys W and V only depend on the challenges, thus they are opened as in Prove,
+// but bound to the cm.Opening instead of A[0] and B[0].
+
+type ZKProof struct {
+	L       []cm.Com   // Blinded left commitments at each level
+	R       []cm.Com   // Blinded right commitments at each level
+	Opening cm.Opening // Opening of the folded commitment
+	W       mcl.G1     // Left commitment key
+	V       mcl.G2     // Right commitment key
+	Pi1     mcl.G1     // Proof of correct evaluation of Halo poly W
+	Pi2     mcl.G2     // Proof of correct evaluation of Halo poly V
+}
+
+// zkLabel separates the transcript of the zero-knowledge mode, see cm.HidingTranscript.
+const zkLabel = "GIPA KZG ZK"
+
+func (self *ZKProof) Append(ComL cm.Com, ComR cm.Com) {
+	self.L = append(self.L, ComL)
+	self.R = append(self.R, ComR)
+}
+
+func (self *ZKProof) At(i uint64) (cm.Com, cm.Com) {
+	return self.L[i], self.R[i]
+}
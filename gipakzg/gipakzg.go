@@ -75,3 +75,15 @@ func EvaluateHaloPoly(RandomChallenges []mcl.Fr, evaluationPoint mcl.Fr, invert
 	}
 	return result
 }
+
+// ScaleChallenges returns the challenges which define the final V when the prover was given V_i^{s^i} instead of V_i.
+// Round k folds V_{i + M/2^{k+1}} with x_k^{-1} s^{M/2^{k+1}}, thus x_k becomes x_k s^{-M/2^{k+1}}.
+func ScaleChallenges(RandomChallenges []mcl.Fr, s mcl.Fr) []mcl.Fr {
+	l := len(RandomChallenges)
+	result := make([]mcl.Fr, l)
+	for k := 0; k < l; k++ {
+		step := utils.FrPow(s, -(int64(1) << (l - k - 1)))
+		mcl.FrMul(&result[k], &RandomChallenges[k], &step)
+	}
+	return result
+}
@@ -1,6 +1,10 @@
 package gipakzg
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -93,3 +97,263 @@ func CompareProofs(piKZG Proof, pi gipa.Proof) bool {
 	}
 	return true
 }
+
+func TestGIPAKZGWorkers(t *testing.T) {
+
+	M := uint64(1) << 6
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaKzgTestSetup(M, alpha, beta, g, h)
+	var parallel Prover
+	parallel.Clone(&prover)
+	parallel.Workers = 4
+
+	proof := prover.Prove()
+	proofParallel := parallel.Prove()
+	data, _ := proof.MarshalBinary()
+	dataParallel, _ := proofParallel.MarshalBinary()
+	if !bytes.Equal(data, dataParallel) {
+		t.Errorf("GIPAKZG Workers: Parallel proof differs from the sequential proof")
+	}
+	if !verifier.Verify(proofParallel) {
+		t.Errorf("GIPAKZG Workers: Parallel proof did not verify")
+	}
+}
+
+func TestGIPAKZGProveContext(t *testing.T) {
+
+	M := uint64(1) << 6
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaKzgTestSetup(M, alpha, beta, g, h)
+	var local, cancelled Prover
+	local.Clone(&prover)
+	cancelled.Clone(&prover) // Prove consumes prover
+	var phases []utils.Progress
+	local.Progress = func(p utils.Progress) { phases = append(phases, p) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	proof, err := local.ProveContext(ctx)
+	if err != nil {
+		t.Fatalf("GIPAKZG ProveContext: %v", err)
+	}
+	want := []utils.Phase{utils.PhaseTransform, utils.PhaseFiatShamir, utils.PhaseFold}
+	if len(phases) != len(want)*6+1 {
+		t.Fatalf("GIPAKZG ProveContext: %d phases reported", len(phases))
+	}
+	for i, p := range phases[:len(want)*6] {
+		if p.Round != i/len(want) || p.M != M>>(i/len(want)) || p.Phase != want[i%len(want)] {
+			t.Errorf("GIPAKZG ProveContext: Unexpected progress %d: %+v", i, p)
+		}
+	}
+	if last := phases[len(phases)-1]; last.Phase != utils.PhaseOpening || last.Round != 6 {
+		t.Errorf("GIPAKZG ProveContext: Unexpected progress of the opening: %+v", last)
+	}
+	data, _ := proof.MarshalBinary()
+	proofProve := prover.Prove()
+	dataProve, _ := proofProve.MarshalBinary()
+	if !bytes.Equal(data, dataProve) {
+		t.Errorf("GIPAKZG ProveContext: Proof differs from Prove")
+	}
+	if !verifier.Verify(proof) {
+		t.Errorf("GIPAKZG ProveContext: Proof did not verify")
+	}
+
+	// Cancel once the first round is folded
+	cancelled.Progress = func(p utils.Progress) {
+		if p.Phase == utils.PhaseFold {
+			cancel()
+		}
+	}
+	if _, err := cancelled.ProveContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GIPAKZG ProveContext: Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGIPAKZGBatchVerify(t *testing.T) {
+
+	M := uint64(1) << 4
+	n := 8
+	alpha, beta, g, h := utils.RunMPC()
+	ck, kzg1, kzg2 := cm.IPPSetupKZG(M, alpha, beta, g, h)
+	coms := make([]cm.Com, n)
+	proofs := make([]Proof, n)
+	var verifier Verifier
+	for i := 0; i < n; i++ {
+		A, B := utils.GenerateData(M)
+		var prover Prover
+		prover, verifier = AssembleProverVerifier(M, ck, kzg1, kzg2, A, B)
+		coms[i] = verifier.Com
+		proofs[i] = prover.Prove()
+	}
+
+	t.Run(fmt.Sprintf("%d/Batch;", n), func(t *testing.T) {
+		if status, i := verifier.BatchVerify(coms, proofs); !status || i != -1 {
+			t.Errorf("GIPAKZG BatchVerify: Failed at %d", i)
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/BatchReject;", n), func(t *testing.T) {
+		tampered := make([]Proof, n)
+		copy(tampered, proofs)
+		mcl.G2Add(&tampered[3].Pi2, &tampered[3].Pi2, &h)
+		if status, i := verifier.BatchVerify(coms, tampered); status || i != 3 {
+			t.Errorf("GIPAKZG BatchVerify: Tampered opening not pinpointed: %v %d", status, i)
+		}
+		tampered[3] = proofs[3]
+		mcl.G1Add(&tampered[6].W, &tampered[6].W, &g)
+		if status, i := verifier.BatchVerify(coms, tampered); status || i != 6 {
+			t.Errorf("GIPAKZG BatchVerify: Tampered key not pinpointed: %v %d", status, i)
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/BatchPrepared;", n), func(t *testing.T) {
+		prepared := utils.NewPreparedVerifyingKey(kzg1.VK)
+		var shared Verifier
+		shared.InitPrepared(M, kzg1, kzg2, cm.Com{}, prepared)
+		shared.VScale = verifier.VScale
+		if status, i := shared.BatchVerify(coms, proofs); !status || i != -1 {
+			t.Errorf("GIPAKZG BatchVerify: Failed at %d with a shared key", i)
+		}
+		if shared.Prepared != prepared {
+			t.Errorf("GIPAKZG InitPrepared: Shared key was replaced")
+		}
+		defer func() {
+			if recover() == nil {
+				t.Errorf("GIPAKZG InitPrepared: Accepted a key of other points")
+			}
+		}()
+		shared.InitPrepared(M, kzg1, kzg2, cm.Com{}, utils.NewPreparedVerifyingKey([]mcl.G2{h}))
+	})
+}
+
+func TestGIPAKZGTerms(t *testing.T) {
+
+	M := uint64(1) << 4
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaKzgTestSetup(M, alpha, beta, g, h)
+	proof := prover.Prove()
+
+	var local Verifier
+	local.Clone(&verifier)
+	if !local.Reduce(proof) {
+		t.Fatalf("GIPAKZG Terms: Reduce failed")
+	}
+	terms := local.Terms(&proof)
+	if !local.CheckTerms(&terms) {
+		t.Errorf("GIPAKZG Terms: Honest proof rejected")
+	}
+
+	// Every equation merged into the multi-pairing is still checked
+	tamper := []func(*Proof){
+		func(p *Proof) { mcl.G1Add(&p.A[0], &p.A[0], &g) },
+		func(p *Proof) { mcl.G2Add(&p.B[0], &p.B[0], &h) },
+		func(p *Proof) { mcl.G1Add(&p.W, &p.W, &g) },
+		func(p *Proof) { mcl.G2Add(&p.V, &p.V, &h) },
+		func(p *Proof) { mcl.G1Add(&p.Pi1, &p.Pi1, &g) },
+		func(p *Proof) { mcl.G2Add(&p.Pi2, &p.Pi2, &h) },
+	}
+	for i, f := range tamper {
+		tampered := proof
+		f(&tampered)
+		local.Clone(&verifier)
+		if local.Verify(tampered) {
+			t.Errorf("GIPAKZG Terms: Tampered proof %d accepted", i)
+		}
+	}
+}
+
+func TestGIPAKZGEncoding(t *testing.T) {
+
+	M := uint64(1) << 4
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := GipaKzgTestSetup(M, alpha, beta, g, h)
+	proof := prover.Prove()
+
+	data, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatalf("GIPA+KZG Encoding: Marshal failed: %v", err)
+	}
+
+	var decoded Proof
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("GIPA+KZG Encoding: Unmarshal failed: %v", err)
+	}
+	again, _ := decoded.MarshalBinary()
+	if !bytes.Equal(data, again) {
+		t.Errorf("GIPA+KZG Encoding: Round trip changed the bytes")
+	}
+	if !verifier.Verify(decoded) {
+		t.Errorf("GIPA+KZG Encoding: Decoded proof did not verify")
+	}
+
+	jsonData, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatalf("GIPA+KZG JSON: Marshal failed: %v", err)
+	}
+	var decodedJSON Proof
+	if err := json.Unmarshal(jsonData, &decodedJSON); err != nil {
+		t.Fatalf("GIPA+KZG JSON: Unmarshal failed: %v", err)
+	}
+	fromJSON, _ := decodedJSON.MarshalBinary()
+	if !bytes.Equal(data, fromJSON) {
+		t.Errorf("GIPA+KZG JSON: Binary form changed after JSON round trip")
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, utils.ErrTruncated) {
+		t.Errorf("GIPA+KZG Encoding: Truncation not detected: %v", err)
+	}
+	if err := decoded.UnmarshalBinary(append(data, 0)); !errors.Is(err, utils.ErrTrailingBytes) {
+		t.Errorf("GIPA+KZG Encoding: Trailing bytes not detected: %v", err)
+	}
+}
+
+func TestGIPAKZGZK(t *testing.T) {
+
+	M := uint64(1) << 4
+	alpha, beta, g, h := utils.RunMPC()
+	ck, kzg1, kzg2, A, B := GenerateGipaKzgInstance(M, alpha, beta, g, h)
+
+	prover := ZKProver{}
+	prover.Init(M, ck, kzg1, kzg2, A, B, cm.RandomBlinding())
+	com := prover.Com
+	proof := prover.Prove()
+
+	t.Run(fmt.Sprintf("%d/ZK;", M), func(t *testing.T) {
+		verifier := ZKVerifier{}
+		verifier.Init(M, kzg1, kzg2, com)
+		if !verifier.Verify(proof) {
+			t.Errorf("GIPAKZG ZK Test: Failed")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/ZKReject;", M), func(t *testing.T) {
+		verifier := ZKVerifier{}
+		verifier.Init(M, kzg1, kzg2, cm.IPPCM(ck, A, B, utils.InnerProd(A, B)))
+		if verifier.Verify(proof) {
+			t.Errorf("GIPAKZG ZK Test: Wrong commitment accepted")
+		}
+		tampered := proof
+		mcl.G1Add(&tampered.W, &tampered.W, &g)
+		verifier.Init(M, kzg1, kzg2, com)
+		if verifier.Verify(tampered) {
+			t.Errorf("GIPAKZG ZK Test: Tampered key accepted")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/ZKSimulator;", M), func(t *testing.T) {
+		// The simulator only gets the statement and chooses the final challenge.
+		simulated, c := SimulateZKProof(M, ck, kzg1, kzg2, com)
+		verifier := ZKVerifier{}
+		verifier.Init(M, kzg1, kzg2, com)
+		if !verifier.Reduce(simulated) {
+			t.Fatalf("GIPAKZG ZK Test: Simulated proof has the wrong shape")
+		}
+		cm.OpeningChallenge(&verifier.Verifier.Transcript, &simulated.Opening)
+		if !verifier.CheckFinal(simulated, c) {
+			t.Errorf("GIPAKZG ZK Test: Simulated transcript rejected")
+		}
+		verifier.Init(M, kzg1, kzg2, com)
+		if verifier.Verify(simulated) {
+			t.Errorf("GIPAKZG ZK Test: Simulated proof accepted under Fiat-Shamir")
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package gipakzg
 
 import (
+	"math/bits"
+
 	"github.com/alinush/go-mcl"
 	"github.com/hyperproofs/gipa-go/cm"
 	"github.com/hyperproofs/gipa-go/utils"
@@ -34,3 +36,41 @@ func GipaKzgTestSetup(mn uint64, alpha mcl.Fr, beta mcl.Fr, g mcl.G1, h mcl.G2)
 	prover, verifier := AssembleProverVerifier(mn, ck, kzg1, kzg2, A, B)
 	return prover, verifier
 }
+
+// SimulateZKProof returns a proof for com and the challenge of its final opening, without knowing A, B or rho.
+// The round messages are random powers of u, like the blinded messages of ZKProver.
+// W, V and their KZG proofs only depend on the challenges and ck.
+// The proof is accepted by ZKVerifier.CheckFinal for the returned challenge.
+func SimulateZKProof(m uint64, ck *cm.Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, com cm.Com) (ZKProof, mcl.Fr) {
+	var proof ZKProof
+	u := cm.HidingBase()
+	var one cm.Com
+	for i := range one.Com {
+		one.Com[i].SetInt64(1)
+	}
+	for i := 0; i < bits.Len64(m-1); i++ {
+		proof.Append(cm.ComBlind(&one, &u, cm.RandomBlinding()), cm.ComBlind(&one, &u, cm.RandomBlinding()))
+	}
+
+	verifier := ZKVerifier{}
+	verifier.Init(m, kzg1, kzg2, com)
+	verifier.Reduce(proof)
+
+	// A prover without witness, only to fold ck and open the final keys.
+	keys := Prover{}
+	keys.Init(m, ck, kzg1, kzg2, make([]mcl.G1, m), make([]mcl.G2, m))
+	keys.RandomChallenges = verifier.Verifier.RandomChallenges
+	for _, x := range keys.RandomChallenges {
+		var y mcl.Fr
+		mcl.FrInv(&y, &x)
+		cm.CkFold(&keys.Ck, x, y, &keys.Ck)
+	}
+
+	var c mcl.Fr
+	c.Random()
+	proof.Opening = cm.SimulateOpening(&keys.Ck, &u, &verifier.Verifier.Com, c)
+	keys.Transcript = verifier.Verifier.Transcript
+	cm.OpeningChallenge(&keys.Transcript, &proof.Opening)
+	proof.W, proof.Pi1, proof.V, proof.Pi2 = keys.OpenKeys(&proof.Opening.A, &proof.Opening.B)
+	return proof, c
+}
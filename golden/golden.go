@@ -0,0 +1,176 @@
+package golden
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperproofs/gipa-go/batch"
+	"github.com/hyperproofs/gipa-go/batchplain"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/gipa"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Golden vectors pin the keys, commitments and proofs derived from fixed seeds.
+// Everything is derived with utils.DRBG, thus any change to the setup, the transcript or the encodings
+// changes the bytes. The reference copies live in testdata and are checked by golden_test.go.
+
+// Vector holds the encodings of one instance:
+// Keys is the v2 key container (cm.WriteKeyContainer), Com is the commitment to A and B (cm.WriteCom)
+// and Proof is the binary encoding of the proof.
+type Vector struct {
+	Name  string
+	Keys  []byte
+	Com   []byte
+	Proof []byte
+}
+
+// All derives the vectors which are checked into testdata.
+func All() ([]Vector, error) {
+
+	var vectors []Vector
+	for _, m := range []uint64{2, 8} {
+		v, err := Gipa(m)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+		v, err = GipaKzg(m)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+	}
+	for _, mn := range [][2]uint32{{2, 4}, {4, 2}} {
+		v, err := Batch(mn[0], mn[1])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+		v, err = BatchPlain(mn[0], mn[1])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+// Gipa derives the GIPA vector of size m.
+func Gipa(m uint64) (Vector, error) {
+
+	v := Vector{Name: fmt.Sprintf("gipa-m%d", m)}
+	rng := utils.NewDRBG(v.Name)
+	alpha, beta, g, h := utils.RunMPCSeeded(rng)
+	ck := cm.IPPSetup(m, alpha, beta, g, h)
+	A, B := utils.GenerateDataSeeded(rng, m)
+	com := cm.IPPCM(ck, A, B, utils.InnerProd(A, B))
+
+	var err error
+	if v.Keys, v.Com, err = encodeKeysCom(ck, nil, nil, &com); err != nil {
+		return v, err
+	}
+
+	prover, verifier := gipa.Prover{}, gipa.Verifier{}
+	prover.Init(m, ck, A, B)
+	verifier.Init(m, ck, com)
+	proof := prover.Prove()
+	if !verifier.Verify(proof) {
+		return v, fmt.Errorf("golden: %s: proof does not verify", v.Name)
+	}
+	v.Proof, err = proof.MarshalBinary()
+	return v, err
+}
+
+// GipaKzg derives the GIPA+KZG vector of size mn.
+func GipaKzg(mn uint64) (Vector, error) {
+
+	v := Vector{Name: fmt.Sprintf("gipakzg-m%d", mn)}
+	rng := utils.NewDRBG(v.Name)
+	alpha, beta, g, h := utils.RunMPCSeeded(rng)
+	ck, kzg1, kzg2 := cm.IPPSetupKZG(mn, alpha, beta, g, h)
+	A, B := utils.GenerateDataSeeded(rng, mn)
+	com := cm.IPPCM(ck, A, B, utils.InnerProd(A, B))
+
+	var err error
+	if v.Keys, v.Com, err = encodeKeysCom(ck, kzg1, kzg2, &com); err != nil {
+		return v, err
+	}
+
+	prover, verifier := gipakzg.Prover{}, gipakzg.Verifier{}
+	prover.Init(mn, ck, kzg1, kzg2, A, B)
+	verifier.Init(mn, kzg1, kzg2, com)
+	proof := prover.Prove()
+	if !verifier.Verify(proof) {
+		return v, fmt.Errorf("golden: %s: proof does not verify", v.Name)
+	}
+	v.Proof, err = proof.MarshalBinary()
+	return v, err
+}
+
+// Batch derives the batch vector for n pairing products of size m.
+func Batch(m uint32, n uint32) (Vector, error) {
+
+	v := Vector{Name: fmt.Sprintf("batch-m%d-n%d", m, n)}
+	rng := utils.NewDRBG(v.Name)
+	mn := uint64(m * n)
+	alpha, beta, g, h := utils.RunMPCSeeded(rng)
+	ck, kzg1, kzg2 := cm.IPPSetupKZG(mn, alpha, beta, g, h)
+	P, Q, A, B := utils.GenerateBatchingDataSeeded(rng, m, n)
+	com := cm.IPPCM(ck, A, B, utils.InnerProd(A, B))
+
+	var err error
+	if v.Keys, v.Com, err = encodeKeysCom(ck, kzg1, kzg2, &com); err != nil {
+		return v, err
+	}
+
+	prover, verifier := batch.Prover{}, batch.Verifier{}
+	prover.Init(m, n, mn, ck, kzg1, kzg2, A, B)
+	verifier.Init(m, n, mn, ck.W, kzg1, kzg2, P, Q, B)
+	proof := prover.Prove()
+	if !verifier.Verify(proof) {
+		return v, fmt.Errorf("golden: %s: proof does not verify", v.Name)
+	}
+	v.Proof, err = proof.MarshalBinary()
+	return v, err
+}
+
+// BatchPlain derives the plain batch vector for n pairing products of size m.
+func BatchPlain(m uint32, n uint32) (Vector, error) {
+
+	v := Vector{Name: fmt.Sprintf("batchplain-m%d-n%d", m, n)}
+	rng := utils.NewDRBG(v.Name)
+	mn := uint64(m * n)
+	alpha, beta, g, h := utils.RunMPCSeeded(rng)
+	ck := cm.IPPSetup(mn, alpha, beta, g, h)
+	P, Q, A, B := utils.GenerateBatchingDataSeeded(rng, m, n)
+	com := cm.IPPCM(ck, A, B, utils.InnerProd(A, B))
+
+	var err error
+	if v.Keys, v.Com, err = encodeKeysCom(ck, nil, nil, &com); err != nil {
+		return v, err
+	}
+
+	prover, verifier := batchplain.Prover{}, batchplain.Verifier{}
+	prover.Init(m, n, mn, ck, A, B)
+	verifier.Init(m, n, mn, ck, P, Q, B)
+	proof := prover.Prove()
+	if !verifier.Verify(proof) {
+		return v, fmt.Errorf("golden: %s: proof does not verify", v.Name)
+	}
+	v.Proof, err = proof.MarshalBinary()
+	return v, err
+}
+
+func encodeKeysCom(ck *cm.Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, com *cm.Com) ([]byte, []byte, error) {
+	var keys, comData bytes.Buffer
+	if _, err := cm.WriteKeyContainer(&keys, ck, kzg1, kzg2); err != nil {
+		return nil, nil, err
+	}
+	if err := cm.WriteCom(&comData, com); err != nil {
+		return nil, nil, err
+	}
+	return keys.Bytes(), comData.Bytes(), nil
+}
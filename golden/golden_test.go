@@ -0,0 +1,119 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Run "go test ./golden -run TestGolden -update" to rewrite testdata after an intended change.
+var update = flag.Bool("update", false, "rewrite the golden vectors in testdata")
+
+const goldenDir = "testdata"
+
+func TestGolden(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	vectors, err := All()
+	if err != nil {
+		t.Fatalf("Golden: Derivation failed: %v", err)
+	}
+	if *update {
+		for _, v := range vectors {
+			for ext, data := range parts(&v) {
+				if err := writeHex(filepath.Join(goldenDir, v.Name+ext), data); err != nil {
+					t.Fatalf("Golden: %v", err)
+				}
+			}
+		}
+		return
+	}
+	if _, err := os.Stat(goldenDir); os.IsNotExist(err) {
+		t.Fatalf("Golden: %s is missing, generate it with scripts/gipa-golden.sh", goldenDir)
+	}
+
+	for _, v := range vectors {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			for ext, data := range parts(&v) {
+				fileName := filepath.Join(goldenDir, v.Name+ext)
+				want, err := readHex(fileName)
+				if err != nil {
+					t.Fatalf("Golden: %v", err)
+				}
+				if !bytes.Equal(data, want) {
+					t.Errorf("Golden: %s differs at byte %d", fileName, firstDifference(data, want))
+				}
+			}
+		})
+	}
+}
+
+// TestGoldenDeterministic derives everything twice. This does not depend on testdata.
+func TestGoldenDeterministic(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	first, err := All()
+	if err != nil {
+		t.Fatalf("Golden: Derivation failed: %v", err)
+	}
+	second, _ := All()
+	for i := range first {
+		a, b := parts(&first[i]), parts(&second[i])
+		for ext := range a {
+			if !bytes.Equal(a[ext], b[ext]) {
+				t.Errorf("Golden: %s%s is not deterministic", first[i].Name, ext)
+			}
+		}
+	}
+}
+
+func parts(v *Vector) map[string][]byte {
+	return map[string][]byte{
+		".keys.hex":  v.Keys,
+		".com.hex":   v.Com,
+		".proof.hex": v.Proof,
+	}
+}
+
+// Vectors are stored as hex, 64 bytes per line, so that diffs stay readable.
+func writeHex(fileName string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(fileName), os.ModePerm); err != nil {
+		return err
+	}
+	var sb strings.Builder
+	for len(data) > 0 {
+		n := 64
+		if n > len(data) {
+			n = len(data)
+		}
+		sb.WriteString(hex.EncodeToString(data[:n]))
+		sb.WriteString("\n")
+		data = data[n:]
+	}
+	return ioutil.WriteFile(fileName, []byte(sb.String()), 0644)
+}
+
+func readHex(fileName string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.Join(strings.Fields(string(data)), ""))
+}
+
+func firstDifference(a []byte, b []byte) int {
+	for i := range a {
+		if i >= len(b) || a[i] != b[i] {
+			return i
+		}
+	}
+	return len(a)
+}
@@ -0,0 +1,104 @@
+package ipa
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// Binary encoding of the IPA proof
+// header (utils.WriteWireHeader) | L[0..rounds) | R[0..rounds) | A[0] | B[0]
+
+// MarshalBinary encodes the proof along with the wire header.
+func (self *Proof) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := self.encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a proof written by MarshalBinary.
+// It rejects truncated input and trailing bytes.
+func (self *Proof) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if err := self.decode(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("IPA Proof: %w: %d bytes", utils.ErrTrailingBytes, r.Len())
+	}
+	return nil
+}
+
+// WriteTo writes the encoded proof to w.
+func (self *Proof) WriteTo(w io.Writer) (int64, error) {
+	data, err := self.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads exactly one encoded proof from r.
+// Unlike UnmarshalBinary, it does not look past the end of the proof. Thus, proofs can be streamed back to back.
+func (self *Proof) ReadFrom(r io.Reader) (int64, error) {
+	cr := utils.CountingReader{R: r}
+	err := self.decode(&cr)
+	return cr.N, err
+}
+
+func (self *Proof) encode(w io.Writer) error {
+
+	if len(self.L) != len(self.R) {
+		return fmt.Errorf("IPA Proof: L and R size mismatch: %d %d", len(self.L), len(self.R))
+	}
+	if err := utils.WriteWireHeader(w, utils.ProofTypeIpa, len(self.L)); err != nil {
+		return err
+	}
+	for i := range self.L {
+		if err := utils.WriteG1(w, &self.L[i]); err != nil {
+			return err
+		}
+	}
+	for i := range self.R {
+		if err := utils.WriteG1(w, &self.R[i]); err != nil {
+			return err
+		}
+	}
+	if err := utils.WriteFr(w, &self.A[0]); err != nil {
+		return err
+	}
+	return utils.WriteFr(w, &self.B[0])
+}
+
+func (self *Proof) decode(r io.Reader) error {
+
+	rounds, err := utils.ReadWireHeader(r, utils.ProofTypeIpa)
+	if err != nil {
+		return fmt.Errorf("IPA Proof: %w", err)
+	}
+	proof := Proof{L: make([]mcl.G1, rounds), R: make([]mcl.G1, rounds)}
+	for i := range proof.L {
+		if err := utils.ReadG1(r, &proof.L[i]); err != nil {
+			return fmt.Errorf("IPA Proof: L[%d]: %w", i, err)
+		}
+	}
+	for i := range proof.R {
+		if err := utils.ReadG1(r, &proof.R[i]); err != nil {
+			return fmt.Errorf("IPA Proof: R[%d]: %w", i, err)
+		}
+	}
+	if err := utils.ReadFr(r, &proof.A[0]); err != nil {
+		return fmt.Errorf("IPA Proof: A: %w", err)
+	}
+	if err := utils.ReadFr(r, &proof.B[0]); err != nil {
+		return fmt.Errorf("IPA Proof: B: %w", err)
+	}
+	*self = proof
+	return nil
+}
@@ -0,0 +1,74 @@
+package ipa
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// roundJSON holds the left and right commitments of one round, as cm.ComRoundJSON.
+type roundJSON struct {
+	Round int    `json:"Round"`
+	L     string `json:"L"`
+	R     string `json:"R"`
+}
+
+type proofJSON struct {
+	utils.WireHeaderJSON
+	Rounds []roundJSON `json:"Rounds"`
+	A      string      `json:"A"`
+	B      string      `json:"B"`
+}
+
+// MarshalJSON encodes the proof with hex group elements and one entry per round.
+func (self Proof) MarshalJSON() ([]byte, error) {
+	if len(self.L) != len(self.R) {
+		return nil, fmt.Errorf("IPA Proof: L and R size mismatch: %d %d", len(self.L), len(self.R))
+	}
+	rounds := make([]roundJSON, len(self.L))
+	for i := range self.L {
+		rounds[i] = roundJSON{i, utils.G1ToHex(&self.L[i]), utils.G1ToHex(&self.R[i])}
+	}
+	out := proofJSON{
+		utils.NewWireHeaderJSON(utils.ProofTypeIpa),
+		rounds,
+		utils.FrToHex(&self.A[0]),
+		utils.FrToHex(&self.B[0]),
+	}
+	return json.Marshal(out)
+}
+
+func (self *Proof) UnmarshalJSON(data []byte) error {
+	var in proofJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("IPA Proof: %w", err)
+	}
+	if err := in.Check(utils.ProofTypeIpa); err != nil {
+		return fmt.Errorf("IPA Proof: %w", err)
+	}
+	if len(in.Rounds) > utils.MaxRounds {
+		return fmt.Errorf("IPA Proof: invalid number of rounds: %d", len(in.Rounds))
+	}
+	proof := Proof{L: make([]mcl.G1, len(in.Rounds)), R: make([]mcl.G1, len(in.Rounds))}
+	for i := range in.Rounds {
+		if in.Rounds[i].Round != i {
+			return fmt.Errorf("IPA Proof: round %d is listed at position %d", in.Rounds[i].Round, i)
+		}
+		if err := utils.G1FromHex(&proof.L[i], in.Rounds[i].L); err != nil {
+			return fmt.Errorf("IPA Proof: L[%d]: %w", i, err)
+		}
+		if err := utils.G1FromHex(&proof.R[i], in.Rounds[i].R); err != nil {
+			return fmt.Errorf("IPA Proof: R[%d]: %w", i, err)
+		}
+	}
+	if err := utils.FrFromHex(&proof.A[0], in.A); err != nil {
+		return fmt.Errorf("IPA Proof: A: %w", err)
+	}
+	if err := utils.FrFromHex(&proof.B[0], in.B); err != nil {
+		return fmt.Errorf("IPA Proof: B: %w", err)
+	}
+	*self = proof
+	return nil
+}
@@ -0,0 +1,154 @@
+package ipa
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Prover is a struct to manage the prover state.
+type Prover struct {
+	M  uint64
+	A  []mcl.Fr
+	B  []mcl.Fr
+	Ck Ck
+	P  mcl.G1 // Commit(ck, a, b, <a, b>)
+
+	MPrime uint64
+	A_L    []mcl.Fr
+	A_R    []mcl.Fr
+	B_L    []mcl.Fr
+	B_R    []mcl.Fr
+	Z_L    mcl.Fr
+	Z_R    mcl.Fr
+	Ck1    Ck
+	Ck2    Ck
+
+	ComL mcl.G1
+	ComR mcl.G1
+	X    []mcl.Fr
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+// Transform is a member function of Prover
+// It performs the following operations:
+// - Breaks the vectors a and b into two equal parts
+// - Computes z_L = <a_R, b_L>, z_R = <a_L, b_R>
+// - Breaks the commitment key into half
+// - Computes the left and right commitment
+// Parameters
+// ----------
+// None
+//
+// Returns
+// -------
+// Updates the data members ComL and ComR.
+// Returns ComL, ComR so that verifier can pose the challenge
+func (self *Prover) Transform() (mcl.G1, mcl.G1) {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+	self.A_L = self.A[:MPrime]
+	self.A_R = self.A[MPrime:]
+	self.B_L = self.B[:MPrime]
+	self.B_R = self.B[MPrime:]
+
+	self.Z_L = InnerProd(self.A_R, self.B_L)
+	self.Z_R = InnerProd(self.A_L, self.B_R)
+
+	self.Ck.Transform(&self.Ck1, &self.Ck2)
+	self.ComL = Commit(&self.Ck1, self.A_R, self.B_L, self.Z_L)
+	self.ComR = Commit(&self.Ck2, self.A_L, self.B_R, self.Z_R)
+
+	return self.ComL, self.ComR
+}
+
+// Fold is a member function of Prover
+// It computes a', b' and ck'
+// Parameters
+// ----------
+// x, Fr the random challenge posed by the verifier
+//
+// Returns
+// -------
+// None
+// Updates the data members A, B, and ck.
+func (self *Prover) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.X = append(self.X, x)
+	self.A = utils.FrFold(x, self.A_R, self.A_L)
+	self.B = utils.FrFold(y, self.B_R, self.B_L)
+
+	CkFold(&self.Ck, x, y, &self.Ck)
+	self.M = self.MPrime
+}
+
+func (self *Prover) FiatShamir() mcl.Fr {
+	var x mcl.Fr
+	// H(Transcript, self.ComL, self.ComR)
+	hash := hashTranscript(self.Transcript[:], self.ComL.Serialize(), self.ComR.Serialize())
+	copy(self.Transcript[:], hash[:])
+	x.SetHashOf(hash[:])
+	return x
+}
+
+func (self *Prover) Prove() Proof {
+	var proof Proof
+
+	m := self.M
+	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
+	i := 0
+	for m > 1 {
+		ComL, ComR := self.Transform()
+		proof.Append(ComL, ComR)
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i++
+	}
+	proof.A[0] = self.A[0]
+	proof.B[0] = self.B[0]
+	return proof
+}
+
+// Init computes P, which the verifier has to be given.
+func (self *Prover) Init(M uint64, ck *Ck, a []mcl.Fr, b []mcl.Fr) {
+
+	utils.InstanceSizeChecker(M, "IPA Prover Init: M is not a power of 2")
+	utils.SizeMismatchCheck(M, ck.M, "IPA Prover Init: CK Size:")
+	utils.SizeMismatchCheck(M, uint64(len(a)), "IPA Prover Init: Vec a Size:")
+	utils.SizeMismatchCheck(M, uint64(len(b)), "IPA Prover Init: Vec b Size:")
+
+	*self = Prover{}
+	self.M = M
+	self.Ck.Clone(ck)
+	self.A = make([]mcl.Fr, M)
+	self.B = make([]mcl.Fr, M)
+	copy(self.A, a)
+	copy(self.B, b)
+	self.P = Commit(&self.Ck, self.A, self.B, InnerProd(self.A, self.B))
+	self.Transcript = statementTranscript(&self.P)
+}
+
+func (self *Prover) Clone(prover *Prover) {
+	self.Init(
+		prover.M,
+		&prover.Ck,
+		prover.A,
+		prover.B)
+}
+
+func hashTranscript(parts ...[]byte) [32]byte {
+	data := make([]byte, 0)
+	for _, part := range parts {
+		data = append(data, part...)
+	}
+	return blake2b.Sum256(data)
+}
@@ -0,0 +1,122 @@
+package ipa
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// Verifier is a struct to manage the verifier state.
+type Verifier struct {
+	M   uint64
+	Ck  Ck
+	Com mcl.G1
+
+	MPrime uint64
+
+	ComL mcl.G1
+	ComR mcl.G1
+
+	X []mcl.Fr
+
+	Transcript [32]byte
+}
+
+// Transform is a member function of Verifier
+// Verifier updates the MPrime
+func (self *Verifier) Transform() {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+}
+
+// Fold is a member function of Verifier
+// Verifier computes the ck' and com'
+// Parameters
+// ----------
+// x, Fr challenge posed to the prover
+//
+// Returns
+// -------
+// None
+// Updates the data members, com and ck
+func (self *Verifier) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.M = self.MPrime
+	self.X = append(self.X, x)
+
+	CkFold(&self.Ck, x, y, &self.Ck)
+	self.Com = ComFold(x, y, &self.ComL, &self.Com, &self.ComR)
+}
+
+func (self *Verifier) FiatShamir() mcl.Fr {
+	var x mcl.Fr
+	// H(Transcript, self.ComL, self.ComR)
+	hash := hashTranscript(self.Transcript[:], self.ComL.Serialize(), self.ComR.Serialize())
+	copy(self.Transcript[:], hash[:])
+	x.SetHashOf(hash[:])
+	return x
+}
+
+func (self *Verifier) Verify(proof Proof) bool {
+
+	m := self.M
+	rounds := bits.Len64(m - 1)
+	if len(proof.L) != rounds || len(proof.R) != rounds {
+		return false
+	}
+	i := uint64(0)
+	for m > 1 {
+		self.Transform()
+		ComL, ComR := proof.At(i)
+		self.Update(ComL, ComR)
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		i = i + 1
+	}
+	return self.Check(proof.A[:1], proof.B[:1])
+}
+
+func (self *Verifier) Update(ComL mcl.G1, ComR mcl.G1) {
+	self.ComR = ComR
+	self.ComL = ComL
+}
+
+// Check is a member function of Verifier
+// It checks com == a G + b H + ab Q for the folded keys.
+// Parameters
+// ----------
+// A, slice mcl.Fr should be of size 1
+// B, slice mcl.Fr should be of size 1
+//
+// Returns
+// -------
+// bool, true if the verification is successful.
+func (self *Verifier) Check(A []mcl.Fr, B []mcl.Fr) bool {
+	comProver := Commit(&self.Ck, A, B, InnerProd(A, B))
+	return self.Com.IsEqual(&comProver)
+}
+
+// Init takes the commitment P = Commit(ck, a, b, <a, b>).
+func (self *Verifier) Init(M uint64, ck *Ck, P mcl.G1) {
+
+	utils.InstanceSizeChecker(M, "IPA Verifier Init: M is not a power of 2")
+	utils.SizeMismatchCheck(M, ck.M, "IPA Verifier Init: CK Size:")
+
+	*self = Verifier{}
+	self.M = M
+	self.Ck.Clone(ck)
+	self.Com = P
+	self.Transcript = statementTranscript(&P)
+}
+
+func (self *Verifier) Clone(verifier *Verifier) {
+	self.Init(
+		verifier.M,
+		&verifier.Ck,
+		verifier.Com)
+}
@@ -0,0 +1,147 @@
+package ipa
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+// Bulletproofs-style inner product argument for a, b in Fr^M.
+// The commitment is the Pedersen vector commitment P = <a, G> + <b, H> + <a, b> Q.
+// The rounds are the ones of gipa with G in place of V and H in place of W:
+// ComL = Commit((G_L, H_R), a_R, b_L), ComR = Commit((G_R, H_L), a_L, b_R)
+// a' = x a_R + a_L, b' = x^{-1} b_R + b_L, G' = x^{-1} G_R + G_L, H' = x H_R + H_L
+// P' = x ComL + P + x^{-1} ComR
+// Unlike gipa, the transcript starts from Hash(P).
+
+// Ck is a struct to hold the Pedersen commitment keys.
+type Ck struct {
+	M uint64
+	G []mcl.G1 // Key for a
+	H []mcl.G1 // Key for b
+	Q mcl.G1   // Key for <a, b>
+}
+
+type Proof struct {
+	L []mcl.G1 // Left commitments at each level
+	R []mcl.G1 // Right commitments at each level
+	A [1]mcl.Fr
+	B [1]mcl.Fr
+}
+
+func (self *Proof) Append(ComL mcl.G1, ComR mcl.G1) {
+	self.L = append(self.L, ComL)
+	self.R = append(self.R, ComR)
+}
+
+func (self *Proof) At(i uint64) (mcl.G1, mcl.G1) {
+	return self.L[i], self.R[i]
+}
+
+// Setup returns keys of size m. Every key is HashAndMapTo of a label and its index,
+// thus nobody knows the discrete logs between them.
+func Setup(m uint64) *Ck {
+	utils.InstanceSizeChecker(m, "IPA Setup: M is not a power of 2")
+	ck := &Ck{M: m, G: make([]mcl.G1, m), H: make([]mcl.G1, m)}
+	for i := uint64(0); i < m; i++ {
+		ck.G[i] = hashToG1("gipa-go IPA G", i)
+		ck.H[i] = hashToG1("gipa-go IPA H", i)
+	}
+	ck.Q = hashToG1("gipa-go IPA Q", 0)
+	return ck
+}
+
+func hashToG1(label string, i uint64) mcl.G1 {
+	var result mcl.G1
+	data := make([]byte, len(label)+8)
+	copy(data, label)
+	binary.LittleEndian.PutUint64(data[len(label):], i)
+	if err := result.HashAndMapTo(data); err != nil {
+		panic(fmt.Sprintf("IPA Setup: %v", err))
+	}
+	return result
+}
+
+// Transform is a member of Ck.
+// Given a ck of size m, it returns two m/2 sized ck which are cross-connected as in cm.Ck.Transform.
+// Ck1 = (ck.G[:m/2], ck.H[m/2:])
+// Ck2 = (ck.G[m/2:], ck.H[:m/2])
+func (ck *Ck) Transform(Ck1 *Ck, Ck2 *Ck) {
+
+	MPrime := ck.M / 2
+	*Ck1 = Ck{MPrime, ck.G[:MPrime], ck.H[MPrime:], ck.Q}
+	*Ck2 = Ck{MPrime, ck.G[MPrime:], ck.H[:MPrime], ck.Q}
+}
+
+func (self *Ck) Clone(ck *Ck) {
+
+	utils.InstanceSizeChecker(ck.M, "IPA Ck: Clone: Error")
+	self.M = ck.M
+	self.G = make([]mcl.G1, len(ck.G))
+	self.H = make([]mcl.G1, len(ck.H))
+	copy(self.G, ck.G)
+	copy(self.H, ck.H)
+	self.Q = ck.Q
+}
+
+// CkFold computes G' = x^{-1} G_R + G_L and H' = x H_R + H_L.
+func CkFold(result *Ck, x mcl.Fr, xInv mcl.Fr, ck *Ck) {
+
+	MPrime := ck.M / 2
+	G_L := ck.G[:MPrime]
+	G_R := ck.G[MPrime:]
+	H_L := ck.H[:MPrime]
+	H_R := ck.H[MPrime:]
+
+	Q := ck.Q
+	*result = Ck{}
+	result.M = MPrime
+	result.G = utils.G1Fold(xInv, G_R, G_L)
+	result.H = utils.G1Fold(x, H_R, H_L)
+	result.Q = Q
+}
+
+// ComFold computes com' = x ComL + com + x^{-1} ComR.
+func ComFold(x mcl.Fr, xInv mcl.Fr, ComL *mcl.G1, C *mcl.G1, ComR *mcl.G1) mcl.G1 {
+	var result, temp mcl.G1
+	mcl.G1Mul(&temp, ComL, &x)
+	mcl.G1Add(&result, C, &temp)
+	mcl.G1Mul(&temp, ComR, &xInv)
+	mcl.G1Add(&result, &result, &temp)
+	return result
+}
+
+// InnerProd returns <a, b>.
+func InnerProd(a []mcl.Fr, b []mcl.Fr) mcl.Fr {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("IPA InnerProd: Size mismatch: %d %d", len(a), len(b)))
+	}
+	var result, temp mcl.Fr
+	for i := range a {
+		mcl.FrMul(&temp, &a[i], &b[i])
+		mcl.FrAdd(&result, &result, &temp)
+	}
+	return result
+}
+
+// Commit computes <a, G> + <b, H> + z Q.
+func Commit(ck *Ck, a []mcl.Fr, b []mcl.Fr, z mcl.Fr) mcl.G1 {
+
+	utils.SizeMismatchCheck(ck.M, uint64(len(a)), "IPA Commit: Vec a Size:")
+	utils.SizeMismatchCheck(ck.M, uint64(len(b)), "IPA Commit: Vec b Size:")
+
+	var result, temp mcl.G1
+	mcl.G1MulVec(&result, ck.G, a)
+	mcl.G1MulVec(&temp, ck.H, b)
+	mcl.G1Add(&result, &result, &temp)
+	mcl.G1Mul(&temp, &ck.Q, &z)
+	mcl.G1Add(&result, &result, &temp)
+	return result
+}
+
+// statementTranscript is the initial transcript for the commitment P.
+func statementTranscript(P *mcl.G1) [32]byte {
+	return hashTranscript([]byte("IPA"), P.Serialize())
+}
@@ -0,0 +1,47 @@
+package ipa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+)
+
+func BenchmarkIPA(b *testing.B) {
+	mcl.InitFromString("bls12-381")
+
+	for _, ell := range []uint8{8, 10, 12, 14} {
+		M := uint64(1) << ell
+		ck, A, B := GenerateIpaInstance(M)
+		prover, verifier := AssembleProverVerifier(M, ck, A, B)
+		var proofs []Proof
+
+		b.Run(fmt.Sprintf("%d/Prove;%d", ell, M), func(b *testing.B) {
+			var proverLocal Prover
+			for bn := 0; bn < b.N; bn++ {
+				proverLocal = Prover{}
+				proverLocal.Clone(&prover)
+				b.StartTimer()
+				proof := proverLocal.Prove()
+				b.StopTimer()
+				proofs = append(proofs, proof)
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d/Verifier;%d", ell, M), func(b *testing.B) {
+			var verifierLocal Verifier
+			var proof Proof
+			for bn := 0; bn < b.N; bn++ {
+				verifierLocal = Verifier{}
+				verifierLocal.Clone(&verifier)
+				proof, proofs = proofs[0], proofs[1:]
+				b.StartTimer()
+				status := verifierLocal.Verify(proof)
+				b.StopTimer()
+				if !status {
+					b.Errorf("IPA Verification failed")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package ipa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+func TestIPA(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	for _, ell := range []uint8{1, 4, 10} {
+		M := uint64(1) << ell
+		ck, a, b := GenerateIpaInstance(M)
+		prover, verifier := AssembleProverVerifier(M, ck, a, b)
+		proof := prover.Prove()
+		var verifierCopy Verifier
+		verifierCopy.Clone(&verifier)
+
+		t.Run(fmt.Sprintf("%d/IPA;", M), func(t *testing.T) {
+			if !verifier.Verify(proof) {
+				t.Errorf("IPA Test: Failed")
+			}
+		})
+
+		t.Run(fmt.Sprintf("%d/IPAReject;", M), func(t *testing.T) {
+			var v Verifier
+			// A commitment with the wrong inner product
+			var z, one mcl.Fr
+			one.SetInt64(1)
+			z = InnerProd(a, b)
+			mcl.FrAdd(&z, &z, &one)
+			v.Init(M, ck, Commit(ck, a, b, z))
+			if v.Verify(proof) {
+				t.Errorf("IPA Test: Wrong inner product accepted")
+			}
+			tampered := proof
+			mcl.FrAdd(&tampered.B[0], &tampered.B[0], &one)
+			v.Clone(&verifierCopy)
+			if v.Verify(tampered) {
+				t.Errorf("IPA Test: Tampered proof accepted")
+			}
+		})
+	}
+}
+
+func TestIPAEncoding(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	M := uint64(1) << 4
+	prover, verifier := IpaTestSetup(M)
+	proof := prover.Prove()
+
+	data, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatalf("IPA Encoding: Marshal failed: %v", err)
+	}
+
+	var decoded Proof
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("IPA Encoding: Unmarshal failed: %v", err)
+	}
+	again, _ := decoded.MarshalBinary()
+	if !bytes.Equal(data, again) {
+		t.Errorf("IPA Encoding: Round trip changed the bytes")
+	}
+	if !verifier.Verify(decoded) {
+		t.Errorf("IPA Encoding: Decoded proof did not verify")
+	}
+
+	jsonData, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatalf("IPA JSON: Marshal failed: %v", err)
+	}
+	var decodedJSON Proof
+	if err := json.Unmarshal(jsonData, &decodedJSON); err != nil {
+		t.Fatalf("IPA JSON: Unmarshal failed: %v", err)
+	}
+	fromJSON, _ := decodedJSON.MarshalBinary()
+	if !bytes.Equal(data, fromJSON) {
+		t.Errorf("IPA JSON: Binary form changed after JSON round trip")
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, utils.ErrTruncated) {
+		t.Errorf("IPA Encoding: Truncation not detected: %v", err)
+	}
+	if err := decoded.UnmarshalBinary(append(data, 0)); !errors.Is(err, utils.ErrTrailingBytes) {
+		t.Errorf("IPA Encoding: Trailing bytes not detected: %v", err)
+	}
+}
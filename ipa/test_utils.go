@@ -0,0 +1,35 @@
+package ipa
+
+import (
+	"github.com/alinush/go-mcl"
+)
+
+// This will return ck, a, and b.
+func GenerateIpaInstance(m uint64) (*Ck, []mcl.Fr, []mcl.Fr) {
+	ck := Setup(m)
+	a := make([]mcl.Fr, m)
+	b := make([]mcl.Fr, m)
+	for i := range a {
+		a[i].Random()
+		b[i].Random()
+	}
+	return ck, a, b
+}
+
+// Create a prover and a verifier. The verifier is given the commitment computed by the prover.
+func AssembleProverVerifier(m uint64, ck *Ck, a []mcl.Fr, b []mcl.Fr) (Prover, Verifier) {
+
+	prover := Prover{}
+	verifier := Verifier{}
+
+	prover.Init(m, ck, a, b)
+	verifier.Init(m, ck, prover.P)
+	return prover, verifier
+}
+
+// Calls the above two functions at once for simple ease. Nothing fancy.
+func IpaTestSetup(m uint64) (Prover, Verifier) {
+	ck, a, b := GenerateIpaInstance(m)
+	prover, verifier := AssembleProverVerifier(m, ck, a, b)
+	return prover, verifier
+}
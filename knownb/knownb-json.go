@@ -0,0 +1,99 @@
+package knownb
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+type comJSON struct {
+	Com [2]string `json:"Com"`
+}
+
+// roundJSON holds the left and right commitments of one round, as cm.ComRoundJSON.
+type roundJSON struct {
+	Round int     `json:"Round"`
+	L     comJSON `json:"L"`
+	R     comJSON `json:"R"`
+}
+
+type proofJSON struct {
+	utils.WireHeaderJSON
+	Rounds []roundJSON `json:"Rounds"`
+	A      string      `json:"A"`
+	V      string      `json:"V"`
+	Pi     string      `json:"Pi"`
+}
+
+func comToJSON(com *Com) comJSON {
+	var out comJSON
+	for i := range com.Com {
+		out.Com[i] = utils.GTToHex(&com.Com[i])
+	}
+	return out
+}
+
+func comFromJSON(com *Com, in comJSON) error {
+	for i := range in.Com {
+		if err := utils.GTFromHex(&com.Com[i], in.Com[i]); err != nil {
+			return fmt.Errorf("Com[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the proof with hex group elements and one entry per round.
+func (self Proof) MarshalJSON() ([]byte, error) {
+	if len(self.L) != len(self.R) {
+		return nil, fmt.Errorf("Known B Proof: L and R size mismatch: %d %d", len(self.L), len(self.R))
+	}
+	rounds := make([]roundJSON, len(self.L))
+	for i := range self.L {
+		rounds[i] = roundJSON{i, comToJSON(&self.L[i]), comToJSON(&self.R[i])}
+	}
+	out := proofJSON{
+		utils.NewWireHeaderJSON(utils.ProofTypeKnownB),
+		rounds,
+		utils.G1ToHex(&self.A),
+		utils.G2ToHex(&self.V),
+		utils.G2ToHex(&self.Pi),
+	}
+	return json.Marshal(out)
+}
+
+func (self *Proof) UnmarshalJSON(data []byte) error {
+	var in proofJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("Known B Proof: %w", err)
+	}
+	if err := in.Check(utils.ProofTypeKnownB); err != nil {
+		return fmt.Errorf("Known B Proof: %w", err)
+	}
+	if len(in.Rounds) > utils.MaxRounds {
+		return fmt.Errorf("Known B Proof: invalid number of rounds: %d", len(in.Rounds))
+	}
+	proof := Proof{L: make([]Com, len(in.Rounds)), R: make([]Com, len(in.Rounds))}
+	for i := range in.Rounds {
+		if in.Rounds[i].Round != i {
+			return fmt.Errorf("Known B Proof: round %d is listed at position %d", in.Rounds[i].Round, i)
+		}
+		if err := comFromJSON(&proof.L[i], in.Rounds[i].L); err != nil {
+			return fmt.Errorf("Known B Proof: L[%d]: %w", i, err)
+		}
+		if err := comFromJSON(&proof.R[i], in.Rounds[i].R); err != nil {
+			return fmt.Errorf("Known B Proof: R[%d]: %w", i, err)
+		}
+	}
+	if err := utils.G1FromHex(&proof.A, in.A); err != nil {
+		return fmt.Errorf("Known B Proof: A: %w", err)
+	}
+	if err := utils.G2FromHex(&proof.V, in.V); err != nil {
+		return fmt.Errorf("Known B Proof: V: %w", err)
+	}
+	if err := utils.G2FromHex(&proof.Pi, in.Pi); err != nil {
+		return fmt.Errorf("Known B Proof: Pi: %w", err)
+	}
+	*self = proof
+	return nil
+}
@@ -0,0 +1,151 @@
+package knownb
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Prover is a struct to manage the prover state.
+type Prover struct {
+	M   uint64
+	A   []mcl.G1
+	B   []mcl.G2
+	V   []mcl.G2
+	Com Com
+
+	MPrime uint64
+	A_L    []mcl.G1
+	A_R    []mcl.G1
+	B_L    []mcl.G2
+	B_R    []mcl.G2
+	V_L    []mcl.G2
+	V_R    []mcl.G2
+
+	ComL Com
+	ComR Com
+	X    []mcl.Fr
+
+	KZG2 *kzg.KZG2Settings // nil in plain mode
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+// Transform is a member function of Prover
+// It splits A, B and V into halves and computes the left and right commitments.
+// Parameters
+// ----------
+// None
+//
+// Returns
+// -------
+// ComL, ComR so that verifier can pose the challenge
+func (self *Prover) Transform() (Com, Com) {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+	self.A_L = self.A[:MPrime]
+	self.A_R = self.A[MPrime:]
+	self.B_L = self.B[:MPrime]
+	self.B_R = self.B[MPrime:]
+	self.V_L = self.V[:MPrime]
+	self.V_R = self.V[MPrime:]
+
+	self.ComL = Com{[2]mcl.GT{utils.InnerProd(self.A_R, self.V_L), utils.InnerProd(self.A_R, self.B_L)}}
+	self.ComR = Com{[2]mcl.GT{utils.InnerProd(self.A_L, self.V_R), utils.InnerProd(self.A_L, self.B_R)}}
+	return self.ComL, self.ComR
+}
+
+// Fold is a member function of Prover
+// It computes A' = x A_R + A_L, B' = x^{-1} B_R + B_L and V' = x^{-1} V_R + V_L
+// Parameters
+// ----------
+// x, Fr the random challenge posed by the verifier
+//
+// Returns
+// -------
+// None
+func (self *Prover) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.X = append(self.X, x)
+	utils.G1FoldInto(self.A_L, x, self.A_R, self.A_L, 0)
+	utils.G2FoldInto(self.B_L, y, self.B_R, self.B_L, 0)
+	utils.G2FoldInto(self.V_L, y, self.V_R, self.V_L, 0)
+	self.A = self.A_L
+	self.B = self.B_L
+	self.V = self.V_L
+	self.M = self.MPrime
+}
+
+func (self *Prover) FiatShamir() mcl.Fr {
+	return roundChallenge(&self.Transcript, &self.ComL, &self.ComR)
+}
+
+func (self *Prover) Prove() Proof {
+	var proof Proof
+
+	m := self.M
+	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
+	i := 0
+	for m > 1 {
+		proof.Append(self.Transform())
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i++
+	}
+	proof.A = self.A[0]
+	if self.KZG2 == nil {
+		return proof
+	}
+
+	// Hash(transcript || A)
+	b := openingChallenge(&self.Transcript, &proof.A)
+	fv := gipakzg.BuildHaloPoly(self.RandomChallenges, true)
+	proof.V = *self.KZG2.CommitToPoly(fv)
+	Pi, _ := self.KZG2.ComputeProofSingle(fv, &b)
+	proof.Pi = *Pi
+	if !proof.V.IsEqual(&self.V[0]) {
+		panic("TIPP known B Prover: V Commitment key computed using TIPP does not match with HaloPoly evaluation.")
+	}
+	return proof
+}
+
+// Init computes com = (<A, ck.V>, <A, B>), which the verifier has to be given.
+// kzg2 is nil in plain mode, where the verifier computes the final V itself.
+func (self *Prover) Init(M uint64, ck *cm.Ck, kzg2 *kzg.KZG2Settings, A []mcl.G1, B []mcl.G2) {
+
+	utils.InstanceSizeChecker(M, "TIPP known B Prover Init: M is not a power of 2")
+	utils.SizeMismatchCheck(M, ck.M, "TIPP known B Prover Init: CK Size:")
+	if kzg2 != nil {
+		utils.SizeMismatchCheck(2*M-1, uint64(len(kzg2.PK)), "TIPP known B Prover Init: KZG2 PK Size:")
+	}
+	utils.SizeMismatchCheck(M, uint64(len(A)), "TIPP known B Prover Init: Vec A Size:")
+	utils.SizeMismatchCheck(M, uint64(len(B)), "TIPP known B Prover Init: Vec B Size:")
+
+	*self = Prover{}
+	self.M = M
+	self.KZG2 = kzg2
+	self.A = make([]mcl.G1, M)
+	self.B = make([]mcl.G2, M)
+	self.V = make([]mcl.G2, M)
+	copy(self.A, A)
+	copy(self.B, B)
+	copy(self.V, ck.V)
+
+	self.Com = Com{[2]mcl.GT{utils.InnerProd(self.A, self.V), utils.InnerProd(self.A, self.B)}}
+	self.Transcript = statementTranscript(&self.Com, self.B)
+}
+
+func (self *Prover) Clone(prover *Prover) {
+	ck := cm.Ck{M: prover.M, V: prover.V}
+	self.Init(prover.M, &ck, prover.KZG2, prover.A, prover.B)
+}
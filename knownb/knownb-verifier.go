@@ -0,0 +1,132 @@
+package knownb
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Verifier is a struct to manage the verifier state.
+type Verifier struct {
+	M   uint64
+	Com Com
+	B   []mcl.G2
+	V   []mcl.G2 // Only in plain mode
+
+	MPrime uint64
+
+	ComL Com
+	ComR Com
+
+	X []mcl.Fr
+
+	KZG2 *kzg.KZG2Settings // nil in plain mode
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+func (self *Verifier) Transform() {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+}
+
+// Fold is a member function of Verifier
+// It records the challenge. com is folded at once with ComFoldAll, B and V in Check.
+func (self *Verifier) Fold(x mcl.Fr) {
+
+	self.M = self.MPrime
+	self.X = append(self.X, x)
+}
+
+func (self *Verifier) FiatShamir() mcl.Fr {
+	return roundChallenge(&self.Transcript, &self.ComL, &self.ComR)
+}
+
+func (self *Verifier) Update(ComL Com, ComR Com) {
+	self.ComL = ComL
+	self.ComR = ComR
+}
+
+func (self *Verifier) Verify(proof Proof) bool {
+
+	m := self.M
+	rounds := bits.Len64(m - 1)
+	if len(proof.L) != rounds || len(proof.R) != rounds {
+		return false
+	}
+	self.RandomChallenges = make([]mcl.Fr, rounds)
+	i := uint64(0)
+	for m > 1 {
+		self.Transform()
+		self.Update(proof.At(i))
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i = i + 1
+	}
+	self.Com = ComFoldAll(&self.Com, proof.L, proof.R, self.X)
+
+	scalars := utils.FoldedScalars(self.RandomChallenges, true)
+	if self.KZG2 == nil {
+		var V mcl.G2
+		mcl.G2MulVec(&V, self.V, scalars)
+		return self.Check(proof.A, V, scalars)
+	}
+
+	status := self.Check(proof.A, proof.V, scalars)
+	// Hash(transcript || A)
+	b := openingChallenge(&self.Transcript, &proof.A)
+	yv := gipakzg.EvaluateHaloPoly(self.RandomChallenges, b, true)
+	status = status && self.KZG2.CheckProofSingle(&proof.V, &proof.Pi, &b, &yv)
+	return status
+}
+
+// Check is a member function of Verifier
+// It computes the final B = sum s_i B_i and checks com == (e(A, V), e(A, B)).
+func (self *Verifier) Check(A mcl.G1, V mcl.G2, scalars []mcl.Fr) bool {
+	var B mcl.G2
+	mcl.G2MulVec(&B, self.B, scalars)
+
+	var com Com
+	mcl.Pairing(&com.Com[0], &A, &V)
+	mcl.Pairing(&com.Com[1], &A, &B)
+	return self.Com.IsEqual(&com)
+}
+
+// Init takes com = (<A, ck.V>, <A, B>) and B. The final V is checked with kzg2.
+func (self *Verifier) Init(M uint64, kzg2 *kzg.KZG2Settings, com Com, B []mcl.G2) {
+
+	utils.InstanceSizeChecker(M, "TIPP known B Verifier Init: M is not a power of 2")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg2.PK)), "TIPP known B Verifier Init: KZG2 PK Size:")
+	utils.SizeMismatchCheck(M, uint64(len(B)), "TIPP known B Verifier Init: Vec B Size:")
+
+	*self = Verifier{}
+	self.M = M
+	self.KZG2 = kzg2
+	self.Com = com
+	self.B = make([]mcl.G2, M)
+	copy(self.B, B)
+	self.Transcript = statementTranscript(&self.Com, self.B)
+}
+
+// InitPlain is Init for plain mode, where the verifier computes the final V from ck.V.
+func (self *Verifier) InitPlain(M uint64, V []mcl.G2, com Com, B []mcl.G2) {
+
+	utils.InstanceSizeChecker(M, "TIPP known B Verifier Init: M is not a power of 2")
+	utils.SizeMismatchCheck(M, uint64(len(V)), "TIPP known B Verifier Init: CK Size:")
+	utils.SizeMismatchCheck(M, uint64(len(B)), "TIPP known B Verifier Init: Vec B Size:")
+
+	*self = Verifier{}
+	self.M = M
+	self.Com = com
+	self.B = make([]mcl.G2, M)
+	self.V = make([]mcl.G2, M)
+	copy(self.B, B)
+	copy(self.V, V)
+	self.Transcript = statementTranscript(&self.Com, self.B)
+}
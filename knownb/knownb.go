@@ -0,0 +1,115 @@
+package knownb
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Single-sided TIPP, where the verifier knows B.
+// Only A is committed: com = (<A, V>, Z) with Z = <A, B>. Each round sends
+// ComL = (<A_R, V_L>, <A_R, B_L>) and ComR = (<A_L, V_R>, <A_L, B_R>), and folds
+// A' = x A_R + A_L, B' = x^{-1} B_R + B_L, V' = x^{-1} V_R + V_L, com' = ComL^x com ComR^{x^{-1}}
+// as in gipa. The verifier computes the final B with one multi-exponentiation, see utils.FoldedScalars.
+// The final V is either opened with KZG2 as in gipakzg, or computed by the verifier like B (plain mode).
+// Compared to gipa and gipakzg there is no commitment to B, no W and thus no KZG1 opening.
+
+// Com is the commitment (<A, V>, <A, B>).
+type Com struct {
+	Com [2]mcl.GT
+}
+
+func (self *Com) IsEqual(com *Com) bool {
+	return self.Com[0].IsEqual(&com.Com[0]) && self.Com[1].IsEqual(&com.Com[1])
+}
+
+// ComFold computes com' = ComL^x * com * ComR^(x^-1)
+func ComFold(x mcl.Fr, xInv mcl.Fr, ComL *Com, C *Com, ComR *Com) Com {
+	result := Com{}
+	var tempL, tempR mcl.GT
+	for i := range result.Com {
+		mcl.GTPow(&tempL, &ComL.Com[i], &x)
+		mcl.GTPow(&tempR, &ComR.Com[i], &xInv)
+		mcl.GTMul(&result.Com[i], &tempL, &C.Com[i])
+		mcl.GTMul(&result.Com[i], &result.Com[i], &tempR)
+	}
+	return result
+}
+
+// ComFoldAll computes the commitment after all the rounds at once, as cm.ComFoldAll.
+func ComFoldAll(C *Com, L []Com, R []Com, X []mcl.Fr) Com {
+
+	rowsL := make([][]mcl.GT, len(L))
+	rowsR := make([][]mcl.GT, len(R))
+	for k := range L {
+		rowsL[k] = L[k].Com[:]
+	}
+	for k := range R {
+		rowsR[k] = R[k].Com[:]
+	}
+	result := Com{}
+	copy(result.Com[:], utils.GTFoldAll(C.Com[:], rowsL, rowsR, X))
+	return result
+}
+
+type Proof struct {
+	L  []Com  // Left commitments at each level
+	R  []Com  // Right commitments at each level
+	A  mcl.G1 // Final value of A after log M rounds
+	V  mcl.G2 // Final commitment key, zero in plain mode
+	Pi mcl.G2 // Proof of correct evaluation of Halo poly V, zero in plain mode
+}
+
+func (self *Proof) Append(ComL Com, ComR Com) {
+	self.L = append(self.L, ComL)
+	self.R = append(self.R, ComR)
+}
+
+func (self *Proof) At(i uint64) (Com, Com) {
+	return self.L[i], self.R[i]
+}
+
+func appendCom(data []byte, com *Com) []byte {
+	for i := range com.Com {
+		data = append(data, com.Com[i].Serialize()...)
+	}
+	return data
+}
+
+// statementTranscript is the initial transcript for the statement: the commitment and the public B.
+// Hashing B prevents the prover from choosing B after the challenges.
+func statementTranscript(com *Com, B []mcl.G2) [32]byte {
+	hash, _ := blake2b.New256(nil)
+	hash.Write(appendCom([]byte("TIPP known B"), com))
+	for i := range B {
+		hash.Write(B[i].Serialize())
+	}
+	var result [32]byte
+	copy(result[:], hash.Sum(nil))
+	return result
+}
+
+// roundChallenge returns x = Hash(transcript || ComL || ComR) and updates the transcript.
+func roundChallenge(transcript *[32]byte, ComL *Com, ComR *Com) mcl.Fr {
+	var x mcl.Fr
+	data := make([]byte, 0)
+	data = append(data, transcript[:]...)
+	data = appendCom(data, ComL)
+	data = appendCom(data, ComR)
+	hash := blake2b.Sum256(data)
+	copy(transcript[:], hash[:])
+	x.SetHashOf(hash[:])
+	return x
+}
+
+// openingChallenge returns b = Hash(transcript || A) and updates the transcript.
+func openingChallenge(transcript *[32]byte, A *mcl.G1) mcl.Fr {
+	var b mcl.Fr
+	data := make([]byte, 0)
+	data = append(data, transcript[:]...)
+	data = append(data, A.Serialize()...)
+	hash := blake2b.Sum256(data)
+	copy(transcript[:], hash[:])
+	b.SetHashOf(hash[:])
+	return b
+}
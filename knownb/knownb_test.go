@@ -0,0 +1,100 @@
+package knownb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+func TestKnownB(t *testing.T) {
+
+	M := uint64(1) << 8
+	alpha, beta, g, h := utils.RunMPC()
+	ck, kzg2, A, B := GenerateKnownBInstance(M, alpha, beta, g, h)
+
+	t.Run(fmt.Sprintf("%d/KZG;", M), func(t *testing.T) {
+		prover, verifier := AssembleProverVerifier(M, ck, kzg2, A, B)
+		proof := prover.Prove()
+		if !verifier.Verify(proof) {
+			t.Errorf("Known B Test: Failed")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/Plain;", M), func(t *testing.T) {
+		prover, verifier := AssembleProverVerifier(M, ck, nil, A, B)
+		proof := prover.Prove()
+		if !verifier.Verify(proof) {
+			t.Errorf("Known B Plain Test: Failed")
+		}
+	})
+
+	t.Run(fmt.Sprintf("%d/Reject;", M), func(t *testing.T) {
+		prover, verifier := AssembleProverVerifier(M, ck, kzg2, A, B)
+		proof := prover.Prove()
+		var verifierB Verifier
+		otherB := make([]mcl.G2, M)
+		copy(otherB, B)
+		mcl.G2Add(&otherB[1], &otherB[1], &h)
+		verifierB.Init(M, kzg2, verifier.Com, otherB)
+		if verifierB.Transcript == statementTranscript(&verifier.Com, B) {
+			t.Errorf("Known B Test: B is not bound to the transcript")
+		}
+		if verifierB.Verify(proof) {
+			t.Errorf("Known B Test: Wrong B accepted")
+		}
+		tampered := proof
+		mcl.G1Add(&tampered.A, &tampered.A, &g)
+		if verifier.Verify(tampered) {
+			t.Errorf("Known B Test: Tampered A accepted")
+		}
+	})
+}
+
+func TestKnownBEncoding(t *testing.T) {
+
+	M := uint64(1) << 4
+	alpha, beta, g, h := utils.RunMPC()
+	prover, verifier := KnownBTestSetup(M, alpha, beta, g, h)
+	proof := prover.Prove()
+
+	data, err := proof.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Known B Encoding: Marshal failed: %v", err)
+	}
+
+	var decoded Proof
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Known B Encoding: Unmarshal failed: %v", err)
+	}
+	again, _ := decoded.MarshalBinary()
+	if !bytes.Equal(data, again) {
+		t.Errorf("Known B Encoding: Round trip changed the bytes")
+	}
+	if !verifier.Verify(decoded) {
+		t.Errorf("Known B Encoding: Decoded proof did not verify")
+	}
+
+	jsonData, err := json.Marshal(proof)
+	if err != nil {
+		t.Fatalf("Known B JSON: Marshal failed: %v", err)
+	}
+	var decodedJSON Proof
+	if err := json.Unmarshal(jsonData, &decodedJSON); err != nil {
+		t.Fatalf("Known B JSON: Unmarshal failed: %v", err)
+	}
+	fromJSON, _ := decodedJSON.MarshalBinary()
+	if !bytes.Equal(data, fromJSON) {
+		t.Errorf("Known B JSON: Binary form changed after JSON round trip")
+	}
+	if err := decoded.UnmarshalBinary(data[:len(data)-1]); !errors.Is(err, utils.ErrTruncated) {
+		t.Errorf("Known B Encoding: Truncation not detected: %v", err)
+	}
+	if err := decoded.UnmarshalBinary(append(data, 0)); !errors.Is(err, utils.ErrTrailingBytes) {
+		t.Errorf("Known B Encoding: Trailing bytes not detected: %v", err)
+	}
+}
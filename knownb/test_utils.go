@@ -0,0 +1,38 @@
+package knownb
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Given alpha, beta, G, H, this will return ck, kzg2, A, and B.
+func GenerateKnownBInstance(mn uint64, alpha mcl.Fr, beta mcl.Fr, g mcl.G1, h mcl.G2) (*cm.Ck, *kzg.KZG2Settings, []mcl.G1, []mcl.G2) {
+	ck, _, kzg2 := cm.IPPSetupKZG(mn, alpha, beta, g, h)
+	A, B := utils.GenerateData(mn)
+	return ck, kzg2, A, B
+}
+
+// Run GenerateKnownBInstance to get ck, A, and B. Using that create a prover and verifier.
+// kzg2 is nil for plain mode.
+func AssembleProverVerifier(m uint64, ck *cm.Ck, kzg2 *kzg.KZG2Settings, A []mcl.G1, B []mcl.G2) (Prover, Verifier) {
+
+	prover := Prover{}
+	verifier := Verifier{}
+
+	prover.Init(m, ck, kzg2, A, B)
+	if kzg2 == nil {
+		verifier.InitPlain(m, ck.V, prover.Com, B)
+	} else {
+		verifier.Init(m, kzg2, prover.Com, B)
+	}
+	return prover, verifier
+}
+
+// Calls the above two functions at once for simple ease. Nothing fancy.
+func KnownBTestSetup(mn uint64, alpha mcl.Fr, beta mcl.Fr, g mcl.G1, h mcl.G2) (Prover, Verifier) {
+	ck, kzg2, A, B := GenerateKnownBInstance(mn, alpha, beta, g, h)
+	prover, verifier := AssembleProverVerifier(mn, ck, kzg2, A, B)
+	return prover, verifier
+}
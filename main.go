@@ -1,10 +1,13 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/bits"
+	"os"
 
 	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/ceremony"
 	"github.com/hyperproofs/gipa-go/cm"
 	"github.com/hyperproofs/gipa-go/utils"
 )
@@ -12,7 +15,19 @@ import (
 func main() {
 	fmt.Println("Hello, World!")
 	mcl.InitFromString("bls12-381")
-	GenKeys()
+	if len(os.Args) < 2 {
+		GenKeys()
+		return
+	}
+	switch os.Args[1] {
+	case "import-ptau":
+		ImportKeys(os.Args[2:])
+	case "ceremony-init", "ceremony-contribute", "ceremony-verify":
+		Ceremony(os.Args[1], os.Args[2:])
+	default:
+		fmt.Println("Unknown command:", os.Args[1])
+		os.Exit(2)
+	}
 }
 
 const MAX_AGG_SIZE = 1 << 19
@@ -22,5 +37,85 @@ func GenKeys() {
 	mn := uint64(MAX_AGG_SIZE) // short circuiting things
 	folderPath := fmt.Sprintf("ck-%02d", bits.Len(MAX_AGG_SIZE)-1)
 	ck, kzg1, kzg2 := cm.IPPSetupKZG(mn, alpha, beta, G, H)
-	cm.IPPSaveCmKzg(ck, kzg1, kzg2, folderPath)
+	err := cm.SaveKeyContainer(folderPath, ck, kzg1, kzg2)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ImportKeys derives the keys from powers-of-tau transcripts instead of RunMPC.
+// Usage: import-ptau -alpha A.ptau -beta B.ptau [-size 19] [-out ck-19]
+func ImportKeys(args []string) {
+	flags := flag.NewFlagSet("import-ptau", flag.ExitOnError)
+	alphaFile := flags.String("alpha", "", "ptau transcript for the G1 powers (alpha)")
+	betaFile := flags.String("beta", "", "ptau transcript for the G2 powers (beta), from a ceremony independent of -alpha")
+	size := flags.Int("size", bits.Len(MAX_AGG_SIZE)-1, "log2 of the maximum instance size")
+	folderPath := flags.String("out", "", "output folder, defaults to ck-<size>")
+	flags.Parse(args)
+
+	if *alphaFile == "" || *betaFile == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *folderPath == "" {
+		*folderPath = fmt.Sprintf("ck-%02d", *size)
+	}
+	ck, kzg1, kzg2, err := cm.ImportPtau(*alphaFile, *betaFile, uint64(1)<<*size)
+	if err != nil {
+		panic(err)
+	}
+	err = cm.SaveKeyContainer(*folderPath, ck, kzg1, kzg2)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Dumped", *folderPath+"/"+cm.KeyContainerFile)
+}
+
+// Ceremony runs one step of the updatable setup, see package ceremony.
+// Usage:
+// ceremony-init [-size 19] -out dir
+// ceremony-contribute -in dir -out dir [-name who]
+// ceremony-verify -in dir
+func Ceremony(command string, args []string) {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	size := flags.Int("size", bits.Len(MAX_AGG_SIZE)-1, "log2 of the maximum instance size")
+	inFolder := flags.String("in", "", "folder with the current keys and transcript")
+	outFolder := flags.String("out", "", "folder for the updated keys and transcript")
+	name := flags.String("name", "", "name of the contributor, recorded in the transcript")
+	flags.Parse(args)
+
+	switch command {
+	case "ceremony-init":
+		if *outFolder == "" {
+			flags.Usage()
+			os.Exit(2)
+		}
+		if err := ceremony.Init(*outFolder, uint64(1)<<*size); err != nil {
+			panic(err)
+		}
+		fmt.Println("Initialized", *outFolder)
+	case "ceremony-contribute":
+		if *inFolder == "" || *outFolder == "" {
+			flags.Usage()
+			os.Exit(2)
+		}
+		c, err := ceremony.Contribute(*inFolder, *outFolder, *name)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("Contribution written to %s\nDigest: %x\n", *outFolder, c.Digest)
+	case "ceremony-verify":
+		if *inFolder == "" {
+			flags.Usage()
+			os.Exit(2)
+		}
+		t, err := ceremony.Verify(*inFolder)
+		if err != nil {
+			panic(err)
+		}
+		for i, c := range t.Contributions {
+			fmt.Printf("%d %q %x\n", i, c.Name, c.Digest)
+		}
+		fmt.Println("Verified", len(t.Contributions), "contributions")
+	}
 }
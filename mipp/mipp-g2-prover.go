@@ -0,0 +1,143 @@
+package mipp
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// ProverG2 is a struct to manage the prover state for B in G2.
+// Folding: B' = B_L + x^{-1} B_R, r' = r_L + x r_R, W' = W_L + x W_R
+type ProverG2 struct {
+	M       uint64
+	B       []mcl.G2
+	Scalars []mcl.Fr // Powers of r, folded along with B
+	W       []mcl.G1
+	T       mcl.GT // <W, B>
+	C       mcl.G2 // sum r^i B_i
+
+	MPrime uint64
+	B_L    []mcl.G2
+	B_R    []mcl.G2
+	S_L    []mcl.Fr
+	S_R    []mcl.Fr
+	W_L    []mcl.G1
+	W_R    []mcl.G1
+
+	TL mcl.GT
+	TR mcl.GT
+	ZL mcl.G2
+	ZR mcl.G2
+	X  []mcl.Fr
+
+	KZG1 kzg.KZG1Settings
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+// Transform is a member function of ProverG2
+// It splits B, the exponents and the key into halves and computes the cross commitments and cross terms.
+// Parameters
+// ----------
+// None
+//
+// Returns
+// -------
+// TL, TR, ZL, ZR so that verifier can pose the challenge
+func (self *ProverG2) Transform() (mcl.GT, mcl.GT, mcl.G2, mcl.G2) {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+	self.B_L = self.B[:MPrime]
+	self.B_R = self.B[MPrime:]
+	self.S_L = self.Scalars[:MPrime]
+	self.S_R = self.Scalars[MPrime:]
+	self.W_L = self.W[:MPrime]
+	self.W_R = self.W[MPrime:]
+
+	self.TL = utils.InnerProd(self.W_R, self.B_L)
+	self.TR = utils.InnerProd(self.W_L, self.B_R)
+	mcl.G2MulVec(&self.ZL, self.B_L, self.S_R)
+	mcl.G2MulVec(&self.ZR, self.B_R, self.S_L)
+
+	return self.TL, self.TR, self.ZL, self.ZR
+}
+
+// Fold is a member function of ProverG2
+// It computes B' = B_L + x^{-1} B_R, r' = r_L + x r_R and W' = W_L + x W_R
+// Parameters
+// ----------
+// x, Fr the random challenge posed by the verifier
+//
+// Returns
+// -------
+// None
+func (self *ProverG2) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.X = append(self.X, x)
+	self.B = utils.G2Fold(y, self.B_R, self.B_L)
+	self.Scalars = utils.FrFold(x, self.S_R, self.S_L)
+	self.W = utils.G1Fold(x, self.W_R, self.W_L)
+	self.M = self.MPrime
+}
+
+func (self *ProverG2) FiatShamir() mcl.Fr {
+	return roundChallenge(&self.Transcript, &self.TL, &self.TR, self.ZL.Serialize(), self.ZR.Serialize())
+}
+
+func (self *ProverG2) Prove() ProofG2 {
+	var proof ProofG2
+
+	m := self.M
+	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
+	i := 0
+	for m > 1 {
+		TL, TR, ZL, ZR := self.Transform()
+		proof.Append(TL, TR, ZL, ZR)
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i++
+	}
+	proof.B = self.B[0]
+
+	// Hash(transcript || B)
+	a := openingChallenge(&self.Transcript, proof.B.Serialize())
+	fw := haloPoly(self.RandomChallenges, false)
+	proof.W = *self.KZG1.CommitToPoly(fw)
+	Pi, _ := self.KZG1.ComputeProofSingle(fw, &a)
+	proof.Pi = *Pi
+	if !proof.W.IsEqual(&self.W[0]) {
+		panic("MIPP G2 Prover: W Commitment key computed using MIPP does not match with HaloPoly evaluation.")
+	}
+	return proof
+}
+
+// Init computes T = <ck.W, B> and C = sum r^i B_i, which the verifier has to be given.
+func (self *ProverG2) Init(M uint64, ck *cm.Ck, kzg1 *kzg.KZG1Settings, B []mcl.G2, r mcl.Fr) {
+
+	utils.InstanceSizeChecker(M, "MIPP G2 Prover Init: M is not a power of 2")
+	utils.SizeMismatchCheck(M, ck.M, "MIPP G2 Prover Init: CK Size:")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg1.PK)), "MIPP G2 Prover Init: KZG1 PK Size:")
+	utils.SizeMismatchCheck(M, uint64(len(B)), "MIPP G2 Prover Init: Vec B Size:")
+
+	*self = ProverG2{}
+	self.M = M
+	self.KZG1 = *kzg1
+	self.B = make([]mcl.G2, M)
+	self.W = make([]mcl.G1, M)
+	copy(self.B, B)
+	copy(self.W, ck.W)
+	self.Scalars = Powers(r, M)
+
+	self.T = utils.InnerProd(self.W, self.B)
+	mcl.G2MulVec(&self.C, self.B, self.Scalars)
+	self.Transcript = statementHash("MIPP G2", &self.T, append(self.C.Serialize(), r.Serialize()...))
+}
@@ -0,0 +1,128 @@
+package mipp
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// VerifierG2 is a struct to manage the verifier state for B in G2.
+type VerifierG2 struct {
+	M uint64
+	T mcl.GT
+	C mcl.G2
+	R mcl.Fr
+
+	MPrime uint64
+
+	TL mcl.GT
+	TR mcl.GT
+	ZL mcl.G2
+	ZR mcl.G2
+
+	X []mcl.Fr
+
+	KZG1 kzg.KZG1Settings
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+func (self *VerifierG2) Transform() {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+}
+
+// Fold is a member function of VerifierG2
+// It computes T' = TL^x T TR^{x^{-1}} and C' = x ZL + C + x^{-1} ZR
+func (self *VerifierG2) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.M = self.MPrime
+	self.X = append(self.X, x)
+
+	var tempL, tempR mcl.GT
+	mcl.GTPow(&tempL, &self.TL, &x)
+	mcl.GTPow(&tempR, &self.TR, &y)
+	mcl.GTMul(&self.T, &self.T, &tempL)
+	mcl.GTMul(&self.T, &self.T, &tempR)
+
+	var zL, zR mcl.G2
+	mcl.G2Mul(&zL, &self.ZL, &x)
+	mcl.G2Mul(&zR, &self.ZR, &y)
+	mcl.G2Add(&self.C, &self.C, &zL)
+	mcl.G2Add(&self.C, &self.C, &zR)
+}
+
+func (self *VerifierG2) FiatShamir() mcl.Fr {
+	return roundChallenge(&self.Transcript, &self.TL, &self.TR, self.ZL.Serialize(), self.ZR.Serialize())
+}
+
+func (self *VerifierG2) Update(TL mcl.GT, TR mcl.GT, ZL mcl.G2, ZR mcl.G2) {
+	self.TL = TL
+	self.TR = TR
+	self.ZL = ZL
+	self.ZR = ZR
+}
+
+func (self *VerifierG2) Verify(proof ProofG2) bool {
+
+	m := self.M
+	rounds := bits.Len64(m - 1)
+	if len(proof.TL) != rounds || len(proof.TR) != rounds || len(proof.ZL) != rounds || len(proof.ZR) != rounds {
+		return false
+	}
+	self.RandomChallenges = make([]mcl.Fr, rounds)
+	i := uint64(0)
+	for m > 1 {
+		self.Transform()
+		self.Update(proof.At(i))
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i = i + 1
+	}
+
+	status := self.Check(proof.B, proof.W)
+
+	// Hash(transcript || B)
+	a := openingChallenge(&self.Transcript, proof.B.Serialize())
+	yw := gipakzg.EvaluateHaloPoly(self.RandomChallenges, a, false)
+	status = status && self.KZG1.CheckProofSingle(&proof.W, &proof.Pi, &a, &yw)
+	return status
+}
+
+// Check is a member function of VerifierG2
+// It checks the folded statement: T == e(W, B) and C == r' B, where r' is the folded exponent.
+func (self *VerifierG2) Check(B mcl.G2, W mcl.G1) bool {
+	var result mcl.GT
+	mcl.Pairing(&result, &W, &B)
+	if !result.IsEqual(&self.T) {
+		return false
+	}
+	var C mcl.G2
+	r := FoldedExponent(self.RandomChallenges, self.R, false)
+	mcl.G2Mul(&C, &B, &r)
+	return C.IsEqual(&self.C)
+}
+
+// Init takes the statement: T = <ck.W, B> and C = sum r^i B_i.
+func (self *VerifierG2) Init(M uint64, kzg1 *kzg.KZG1Settings, T mcl.GT, C mcl.G2, r mcl.Fr) {
+
+	utils.InstanceSizeChecker(M, "MIPP G2 Verifier Init: M is not a power of 2")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg1.PK)), "MIPP G2 Verifier Init: KZG1 PK Size:")
+
+	*self = VerifierG2{}
+	self.M = M
+	self.KZG1 = *kzg1
+	self.T = T
+	self.C = C
+	self.R = r
+	self.Transcript = statementHash("MIPP G2", &self.T, append(self.C.Serialize(), r.Serialize()...))
+}
@@ -0,0 +1,142 @@
+package mipp
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Prover is a struct to manage the prover state.
+type Prover struct {
+	M       uint64
+	A       []mcl.G1
+	Scalars []mcl.Fr // Powers of r, folded along with A
+	V       []mcl.G2
+	T       mcl.GT // <A, V>
+	C       mcl.G1 // sum r^i A_i
+
+	MPrime uint64
+	A_L    []mcl.G1
+	A_R    []mcl.G1
+	S_L    []mcl.Fr
+	S_R    []mcl.Fr
+	V_L    []mcl.G2
+	V_R    []mcl.G2
+
+	TL mcl.GT
+	TR mcl.GT
+	ZL mcl.G1
+	ZR mcl.G1
+	X  []mcl.Fr
+
+	KZG2 kzg.KZG2Settings
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+// Transform is a member function of Prover
+// It splits A, the exponents and the key into halves and computes the cross commitments and cross terms.
+// Parameters
+// ----------
+// None
+//
+// Returns
+// -------
+// TL, TR, ZL, ZR so that verifier can pose the challenge
+func (self *Prover) Transform() (mcl.GT, mcl.GT, mcl.G1, mcl.G1) {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+	self.A_L = self.A[:MPrime]
+	self.A_R = self.A[MPrime:]
+	self.S_L = self.Scalars[:MPrime]
+	self.S_R = self.Scalars[MPrime:]
+	self.V_L = self.V[:MPrime]
+	self.V_R = self.V[MPrime:]
+
+	self.TL = utils.InnerProd(self.A_R, self.V_L)
+	self.TR = utils.InnerProd(self.A_L, self.V_R)
+	mcl.G1MulVec(&self.ZL, self.A_R, self.S_L)
+	mcl.G1MulVec(&self.ZR, self.A_L, self.S_R)
+
+	return self.TL, self.TR, self.ZL, self.ZR
+}
+
+// Fold is a member function of Prover
+// It computes A' = A_L + x A_R, r' = r_L + x^{-1} r_R and V' = V_L + x^{-1} V_R
+// Parameters
+// ----------
+// x, Fr the random challenge posed by the verifier
+//
+// Returns
+// -------
+// None
+func (self *Prover) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.X = append(self.X, x)
+	self.A = utils.G1Fold(x, self.A_R, self.A_L)
+	self.Scalars = utils.FrFold(y, self.S_R, self.S_L)
+	self.V = utils.G2Fold(y, self.V_R, self.V_L)
+	self.M = self.MPrime
+}
+
+func (self *Prover) FiatShamir() mcl.Fr {
+	return roundChallenge(&self.Transcript, &self.TL, &self.TR, self.ZL.Serialize(), self.ZR.Serialize())
+}
+
+func (self *Prover) Prove() Proof {
+	var proof Proof
+
+	m := self.M
+	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
+	i := 0
+	for m > 1 {
+		TL, TR, ZL, ZR := self.Transform()
+		proof.Append(TL, TR, ZL, ZR)
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i++
+	}
+	proof.A = self.A[0]
+
+	// Hash(transcript || A)
+	b := openingChallenge(&self.Transcript, proof.A.Serialize())
+	fv := haloPoly(self.RandomChallenges, true)
+	proof.V = *self.KZG2.CommitToPoly(fv)
+	Pi, _ := self.KZG2.ComputeProofSingle(fv, &b)
+	proof.Pi = *Pi
+	if !proof.V.IsEqual(&self.V[0]) {
+		panic("MIPP Prover: V Commitment key computed using MIPP does not match with HaloPoly evaluation.")
+	}
+	return proof
+}
+
+// Init computes T = <A, ck.V> and C = sum r^i A_i, which the verifier has to be given.
+func (self *Prover) Init(M uint64, ck *cm.Ck, kzg2 *kzg.KZG2Settings, A []mcl.G1, r mcl.Fr) {
+
+	utils.InstanceSizeChecker(M, "MIPP Prover Init: M is not a power of 2")
+	utils.SizeMismatchCheck(M, ck.M, "MIPP Prover Init: CK Size:")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg2.PK)), "MIPP Prover Init: KZG2 PK Size:")
+	utils.SizeMismatchCheck(M, uint64(len(A)), "MIPP Prover Init: Vec A Size:")
+
+	*self = Prover{}
+	self.M = M
+	self.KZG2 = *kzg2
+	self.A = make([]mcl.G1, M)
+	self.V = make([]mcl.G2, M)
+	copy(self.A, A)
+	copy(self.V, ck.V)
+	self.Scalars = Powers(r, M)
+
+	self.T = utils.InnerProd(self.A, self.V)
+	mcl.G1MulVec(&self.C, self.A, self.Scalars)
+	self.Transcript = statementHash("MIPP G1", &self.T, append(self.C.Serialize(), r.Serialize()...))
+}
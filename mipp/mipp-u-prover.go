@@ -0,0 +1,164 @@
+package mipp
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// ProverU is a struct to manage the MIPP_u prover state.
+// Folding: A' = A_L + x A_R, b' = b_L + x^{-1} b_R and ck' = cm.CkFold(ck, x, x^{-1})
+type ProverU struct {
+	M  uint64
+	A  []mcl.G1
+	B  []mcl.Fr
+	Ck cm.Ck
+	T  mcl.GT // <A, V>
+	U  mcl.G1 // sum b_i W_i
+	C  mcl.G1 // sum b_i A_i
+
+	MPrime uint64
+	A_L    []mcl.G1
+	A_R    []mcl.G1
+	B_L    []mcl.Fr
+	B_R    []mcl.Fr
+
+	TL mcl.GT
+	TR mcl.GT
+	UL mcl.G1
+	UR mcl.G1
+	ZL mcl.G1
+	ZR mcl.G1
+	X  []mcl.Fr
+
+	KZG1 kzg.KZG1Settings
+	KZG2 kzg.KZG2Settings
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+// Transform is a member function of ProverU
+// It splits A, b and the key into halves and computes the cross commitments and cross terms.
+// Parameters
+// ----------
+// None
+//
+// Returns
+// -------
+// TL, TR, UL, UR, ZL, ZR so that verifier can pose the challenge
+func (self *ProverU) Transform() (mcl.GT, mcl.GT, mcl.G1, mcl.G1, mcl.G1, mcl.G1) {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+	self.A_L = self.A[:MPrime]
+	self.A_R = self.A[MPrime:]
+	self.B_L = self.B[:MPrime]
+	self.B_R = self.B[MPrime:]
+	V_L, V_R := self.Ck.V[:MPrime], self.Ck.V[MPrime:]
+	W_L, W_R := self.Ck.W[:MPrime], self.Ck.W[MPrime:]
+
+	self.TL = utils.InnerProd(self.A_R, V_L)
+	self.TR = utils.InnerProd(self.A_L, V_R)
+	mcl.G1MulVec(&self.UL, W_R, self.B_L)
+	mcl.G1MulVec(&self.UR, W_L, self.B_R)
+	mcl.G1MulVec(&self.ZL, self.A_R, self.B_L)
+	mcl.G1MulVec(&self.ZR, self.A_L, self.B_R)
+
+	return self.TL, self.TR, self.UL, self.UR, self.ZL, self.ZR
+}
+
+// Fold is a member function of ProverU
+// It computes A', b' and ck'
+// Parameters
+// ----------
+// x, Fr the random challenge posed by the verifier
+//
+// Returns
+// -------
+// None
+func (self *ProverU) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.X = append(self.X, x)
+	self.A = utils.G1Fold(x, self.A_R, self.A_L)
+	self.B = utils.FrFold(y, self.B_R, self.B_L)
+
+	cm.CkFold(&self.Ck, x, y, &self.Ck)
+	self.M = self.MPrime
+}
+
+func (self *ProverU) FiatShamir() mcl.Fr {
+	left := append(self.UL.Serialize(), self.ZL.Serialize()...)
+	right := append(self.UR.Serialize(), self.ZR.Serialize()...)
+	return roundChallenge(&self.Transcript, &self.TL, &self.TR, left, right)
+}
+
+func (self *ProverU) Prove() ProofU {
+	var proof ProofU
+
+	m := self.M
+	self.RandomChallenges = make([]mcl.Fr, bits.Len64(m-1))
+	i := 0
+	for m > 1 {
+		proof.Append(self.Transform())
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i++
+	}
+	proof.A = self.A[0]
+	proof.B = self.B[0]
+
+	// Hash(transcript || A || b)
+	a := openingChallenge(&self.Transcript, append(proof.A.Serialize(), proof.B.Serialize()...))
+	fw := haloPoly(self.RandomChallenges, false)
+	proof.W = *self.KZG1.CommitToPoly(fw)
+	Pi1, _ := self.KZG1.ComputeProofSingle(fw, &a)
+	proof.Pi1 = *Pi1
+	if !proof.W.IsEqual(&self.Ck.W[0]) {
+		panic("MIPP_u Prover: W Commitment key computed using MIPP does not match with HaloPoly evaluation.")
+	}
+
+	// Hash(transcript || Pi1)
+	b := openingChallenge(&self.Transcript, proof.Pi1.Serialize())
+	fv := haloPoly(self.RandomChallenges, true)
+	proof.V = *self.KZG2.CommitToPoly(fv)
+	Pi2, _ := self.KZG2.ComputeProofSingle(fv, &b)
+	proof.Pi2 = *Pi2
+	if !proof.V.IsEqual(&self.Ck.V[0]) {
+		panic("MIPP_u Prover: V Commitment key computed using MIPP does not match with HaloPoly evaluation.")
+	}
+	return proof
+}
+
+// Init computes T = <A, ck.V>, U = sum b_i W_i and C = sum b_i A_i, which the verifier has to be given.
+func (self *ProverU) Init(M uint64, ck *cm.Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, A []mcl.G1, b []mcl.Fr) {
+
+	utils.InstanceSizeChecker(M, "MIPP_u Prover Init: M is not a power of 2")
+	utils.SizeMismatchCheck(M, ck.M, "MIPP_u Prover Init: CK Size:")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg1.PK)), "MIPP_u Prover Init: KZG1 PK Size:")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg2.PK)), "MIPP_u Prover Init: KZG2 PK Size:")
+	utils.SizeMismatchCheck(M, uint64(len(A)), "MIPP_u Prover Init: Vec A Size:")
+	utils.SizeMismatchCheck(M, uint64(len(b)), "MIPP_u Prover Init: Vec b Size:")
+
+	*self = ProverU{}
+	self.M = M
+	self.Ck.Clone(ck)
+	self.KZG1 = *kzg1
+	self.KZG2 = *kzg2
+	self.A = make([]mcl.G1, M)
+	self.B = make([]mcl.Fr, M)
+	copy(self.A, A)
+	copy(self.B, b)
+
+	self.T = utils.InnerProd(self.A, self.Ck.V)
+	self.U = CommitScalars(&self.Ck, self.B)
+	mcl.G1MulVec(&self.C, self.A, self.B)
+	self.Transcript = statementHash("MIPP U", &self.T, append(self.U.Serialize(), self.C.Serialize()...))
+}
@@ -0,0 +1,150 @@
+package mipp
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// VerifierU is a struct to manage the MIPP_u verifier state.
+type VerifierU struct {
+	M uint64
+	T mcl.GT
+	U mcl.G1
+	C mcl.G1
+
+	MPrime uint64
+
+	TL mcl.GT
+	TR mcl.GT
+	UL mcl.G1
+	UR mcl.G1
+	ZL mcl.G1
+	ZR mcl.G1
+
+	X []mcl.Fr
+
+	KZG1 kzg.KZG1Settings
+	KZG2 kzg.KZG2Settings
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+func (self *VerifierU) Transform() {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+}
+
+// Fold is a member function of VerifierU
+// It computes T' = TL^x T TR^{x^{-1}}, U' = x UL + U + x^{-1} UR and C' = x ZL + C + x^{-1} ZR
+func (self *VerifierU) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.M = self.MPrime
+	self.X = append(self.X, x)
+
+	var tempL, tempR mcl.GT
+	mcl.GTPow(&tempL, &self.TL, &x)
+	mcl.GTPow(&tempR, &self.TR, &y)
+	mcl.GTMul(&self.T, &self.T, &tempL)
+	mcl.GTMul(&self.T, &self.T, &tempR)
+
+	self.U = foldG1(x, y, &self.UL, &self.U, &self.UR)
+	self.C = foldG1(x, y, &self.ZL, &self.C, &self.ZR)
+}
+
+// foldG1 returns x L + C + y R.
+func foldG1(x mcl.Fr, y mcl.Fr, L *mcl.G1, C *mcl.G1, R *mcl.G1) mcl.G1 {
+	var result, temp mcl.G1
+	mcl.G1Mul(&temp, L, &x)
+	mcl.G1Add(&result, C, &temp)
+	mcl.G1Mul(&temp, R, &y)
+	mcl.G1Add(&result, &result, &temp)
+	return result
+}
+
+func (self *VerifierU) FiatShamir() mcl.Fr {
+	left := append(self.UL.Serialize(), self.ZL.Serialize()...)
+	right := append(self.UR.Serialize(), self.ZR.Serialize()...)
+	return roundChallenge(&self.Transcript, &self.TL, &self.TR, left, right)
+}
+
+func (self *VerifierU) Update(TL mcl.GT, TR mcl.GT, UL mcl.G1, UR mcl.G1, ZL mcl.G1, ZR mcl.G1) {
+	self.TL = TL
+	self.TR = TR
+	self.UL = UL
+	self.UR = UR
+	self.ZL = ZL
+	self.ZR = ZR
+}
+
+func (self *VerifierU) Verify(proof ProofU) bool {
+
+	m := self.M
+	rounds := bits.Len64(m - 1)
+	if len(proof.TL) != rounds || len(proof.TR) != rounds || len(proof.UL) != rounds ||
+		len(proof.UR) != rounds || len(proof.ZL) != rounds || len(proof.ZR) != rounds {
+		return false
+	}
+	self.RandomChallenges = make([]mcl.Fr, rounds)
+	i := uint64(0)
+	for m > 1 {
+		self.Transform()
+		self.Update(proof.At(i))
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i = i + 1
+	}
+
+	status := self.Check(proof.A, proof.B, proof.W, proof.V)
+
+	// Hash(transcript || A || b)
+	a := openingChallenge(&self.Transcript, append(proof.A.Serialize(), proof.B.Serialize()...))
+	// Hash(transcript || Pi1)
+	b := openingChallenge(&self.Transcript, proof.Pi1.Serialize())
+
+	yw := gipakzg.EvaluateHaloPoly(self.RandomChallenges, a, false)
+	yv := gipakzg.EvaluateHaloPoly(self.RandomChallenges, b, true)
+	status = status && self.KZG1.CheckProofSingle(&proof.W, &proof.Pi1, &a, &yw)
+	status = status && self.KZG2.CheckProofSingle(&proof.V, &proof.Pi2, &b, &yv)
+	return status
+}
+
+// Check is a member function of VerifierU
+// It checks the folded statement: T == e(A, V), U == b W and C == b A.
+func (self *VerifierU) Check(A mcl.G1, b mcl.Fr, W mcl.G1, V mcl.G2) bool {
+	var result mcl.GT
+	mcl.Pairing(&result, &A, &V)
+	if !result.IsEqual(&self.T) {
+		return false
+	}
+	var U, C mcl.G1
+	mcl.G1Mul(&U, &W, &b)
+	mcl.G1Mul(&C, &A, &b)
+	return U.IsEqual(&self.U) && C.IsEqual(&self.C)
+}
+
+// Init takes the statement: T = <A, ck.V>, U = sum b_i W_i and C = sum b_i A_i.
+func (self *VerifierU) Init(M uint64, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, T mcl.GT, U mcl.G1, C mcl.G1) {
+
+	utils.InstanceSizeChecker(M, "MIPP_u Verifier Init: M is not a power of 2")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg1.PK)), "MIPP_u Verifier Init: KZG1 PK Size:")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg2.PK)), "MIPP_u Verifier Init: KZG2 PK Size:")
+
+	*self = VerifierU{}
+	self.M = M
+	self.KZG1 = *kzg1
+	self.KZG2 = *kzg2
+	self.T = T
+	self.U = U
+	self.C = C
+	self.Transcript = statementHash("MIPP U", &self.T, append(self.U.Serialize(), self.C.Serialize()...))
+}
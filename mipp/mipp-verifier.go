@@ -0,0 +1,128 @@
+package mipp
+
+import (
+	"math/bits"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Verifier is a struct to manage the verifier state.
+type Verifier struct {
+	M uint64
+	T mcl.GT
+	C mcl.G1
+	R mcl.Fr
+
+	MPrime uint64
+
+	TL mcl.GT
+	TR mcl.GT
+	ZL mcl.G1
+	ZR mcl.G1
+
+	X []mcl.Fr
+
+	KZG2 kzg.KZG2Settings
+
+	Transcript       [32]byte
+	RandomChallenges []mcl.Fr
+}
+
+func (self *Verifier) Transform() {
+	MPrime := self.M / 2
+	self.MPrime = MPrime
+}
+
+// Fold is a member function of Verifier
+// It computes T' = TL^x T TR^{x^{-1}} and C' = x ZL + C + x^{-1} ZR
+func (self *Verifier) Fold(x mcl.Fr) {
+
+	var y mcl.Fr
+	mcl.FrInv(&y, &x)
+
+	self.M = self.MPrime
+	self.X = append(self.X, x)
+
+	var tempL, tempR mcl.GT
+	mcl.GTPow(&tempL, &self.TL, &x)
+	mcl.GTPow(&tempR, &self.TR, &y)
+	mcl.GTMul(&self.T, &self.T, &tempL)
+	mcl.GTMul(&self.T, &self.T, &tempR)
+
+	var zL, zR mcl.G1
+	mcl.G1Mul(&zL, &self.ZL, &x)
+	mcl.G1Mul(&zR, &self.ZR, &y)
+	mcl.G1Add(&self.C, &self.C, &zL)
+	mcl.G1Add(&self.C, &self.C, &zR)
+}
+
+func (self *Verifier) FiatShamir() mcl.Fr {
+	return roundChallenge(&self.Transcript, &self.TL, &self.TR, self.ZL.Serialize(), self.ZR.Serialize())
+}
+
+func (self *Verifier) Update(TL mcl.GT, TR mcl.GT, ZL mcl.G1, ZR mcl.G1) {
+	self.TL = TL
+	self.TR = TR
+	self.ZL = ZL
+	self.ZR = ZR
+}
+
+func (self *Verifier) Verify(proof Proof) bool {
+
+	m := self.M
+	rounds := bits.Len64(m - 1)
+	if len(proof.TL) != rounds || len(proof.TR) != rounds || len(proof.ZL) != rounds || len(proof.ZR) != rounds {
+		return false
+	}
+	self.RandomChallenges = make([]mcl.Fr, rounds)
+	i := uint64(0)
+	for m > 1 {
+		self.Transform()
+		self.Update(proof.At(i))
+		x := self.FiatShamir()
+		self.Fold(x)
+		m = m / 2
+		self.RandomChallenges[i] = x
+		i = i + 1
+	}
+
+	status := self.Check(proof.A, proof.V)
+
+	// Hash(transcript || A)
+	b := openingChallenge(&self.Transcript, proof.A.Serialize())
+	yv := gipakzg.EvaluateHaloPoly(self.RandomChallenges, b, true)
+	status = status && self.KZG2.CheckProofSingle(&proof.V, &proof.Pi, &b, &yv)
+	return status
+}
+
+// Check is a member function of Verifier
+// It checks the folded statement: T == e(A, V) and C == r' A, where r' is the folded exponent.
+func (self *Verifier) Check(A mcl.G1, V mcl.G2) bool {
+	var result mcl.GT
+	mcl.Pairing(&result, &A, &V)
+	if !result.IsEqual(&self.T) {
+		return false
+	}
+	var C mcl.G1
+	r := FoldedExponent(self.RandomChallenges, self.R, true)
+	mcl.G1Mul(&C, &A, &r)
+	return C.IsEqual(&self.C)
+}
+
+// Init takes the statement: T = <A, ck.V> and C = sum r^i A_i.
+func (self *Verifier) Init(M uint64, kzg2 *kzg.KZG2Settings, T mcl.GT, C mcl.G1, r mcl.Fr) {
+
+	utils.InstanceSizeChecker(M, "MIPP Verifier Init: M is not a power of 2")
+	utils.SizeMismatchCheck(2*M-1, uint64(len(kzg2.PK)), "MIPP Verifier Init: KZG2 PK Size:")
+
+	*self = Verifier{}
+	self.M = M
+	self.KZG2 = *kzg2
+	self.T = T
+	self.C = C
+	self.R = r
+	self.Transcript = statementHash("MIPP G1", &self.T, append(self.C.Serialize(), r.Serialize()...))
+}
@@ -0,0 +1,185 @@
+package mipp
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/utils"
+	"golang.org/x/crypto/blake2b"
+)
+
+// MIPP with a known, structured exponent vector (SnarkPack, Section 5).
+// It proves C = sum r^i A_i for a vector A committed as T = <A, V>, where V is ck.V of cm.IPPSetupKZG.
+// Each round halves A, the exponents and the key:
+// A' = A_L + x A_R, r' = r_L + x^{-1} r_R, V' = V_L + x^{-1} V_R
+// The final exponent is a product of log M terms and the final key is checked with a KZG opening
+// of the halo polynomial, thus the verifier runs in O(log M).
+// ProverG2 and VerifierG2 are the same argument for B in G2, committed as T = <W, B>.
+//
+// ProverU and VerifierU are MIPP_u, where the exponents b are not public but committed as U = sum b_i W_i.
+// The keys are folded with cm.CkFold and the final keys are checked as in gipakzg.
+
+// Proof for A in G1.
+type Proof struct {
+	TL []mcl.GT // Left commitments at each level: <A_R, V_L>
+	TR []mcl.GT // Right commitments at each level: <A_L, V_R>
+	ZL []mcl.G1 // Left cross terms at each level: <A_R, r_L>
+	ZR []mcl.G1 // Right cross terms at each level: <A_L, r_R>
+	A  mcl.G1   // Final value of A after log M rounds
+	V  mcl.G2   // Final commitment key
+	Pi mcl.G2   // Proof of correct evaluation of Halo poly V
+}
+
+func (self *Proof) Append(TL mcl.GT, TR mcl.GT, ZL mcl.G1, ZR mcl.G1) {
+	self.TL = append(self.TL, TL)
+	self.TR = append(self.TR, TR)
+	self.ZL = append(self.ZL, ZL)
+	self.ZR = append(self.ZR, ZR)
+}
+
+func (self *Proof) At(i uint64) (mcl.GT, mcl.GT, mcl.G1, mcl.G1) {
+	return self.TL[i], self.TR[i], self.ZL[i], self.ZR[i]
+}
+
+// ProofG2 for B in G2.
+type ProofG2 struct {
+	TL []mcl.GT // Left commitments at each level: <W_R, B_L>
+	TR []mcl.GT // Right commitments at each level: <W_L, B_R>
+	ZL []mcl.G2 // Left cross terms at each level: <B_L, r_R>
+	ZR []mcl.G2 // Right cross terms at each level: <B_R, r_L>
+	B  mcl.G2   // Final value of B after log M rounds
+	W  mcl.G1   // Final commitment key
+	Pi mcl.G1   // Proof of correct evaluation of Halo poly W
+}
+
+func (self *ProofG2) Append(TL mcl.GT, TR mcl.GT, ZL mcl.G2, ZR mcl.G2) {
+	self.TL = append(self.TL, TL)
+	self.TR = append(self.TR, TR)
+	self.ZL = append(self.ZL, ZL)
+	self.ZR = append(self.ZR, ZR)
+}
+
+func (self *ProofG2) At(i uint64) (mcl.GT, mcl.GT, mcl.G2, mcl.G2) {
+	return self.TL[i], self.TR[i], self.ZL[i], self.ZR[i]
+}
+
+// ProofU for C = sum b_i A_i, where b is only known through U = sum b_i W_i.
+type ProofU struct {
+	TL  []mcl.GT // Left commitments to A at each level: <A_R, V_L>
+	TR  []mcl.GT // Right commitments to A at each level: <A_L, V_R>
+	UL  []mcl.G1 // Left commitments to b at each level: <b_L, W_R>
+	UR  []mcl.G1 // Right commitments to b at each level: <b_R, W_L>
+	ZL  []mcl.G1 // Left cross terms at each level: <A_R, b_L>
+	ZR  []mcl.G1 // Right cross terms at each level: <A_L, b_R>
+	A   mcl.G1   // Final value of A after log M rounds
+	B   mcl.Fr   // Final value of b after log M rounds
+	W   mcl.G1   // Left commitment key
+	V   mcl.G2   // Right commitment key
+	Pi1 mcl.G1   // Proof of correct evaluation of Halo poly W
+	Pi2 mcl.G2   // Proof of correct evaluation of Halo poly V
+}
+
+func (self *ProofU) Append(TL mcl.GT, TR mcl.GT, UL mcl.G1, UR mcl.G1, ZL mcl.G1, ZR mcl.G1) {
+	self.TL = append(self.TL, TL)
+	self.TR = append(self.TR, TR)
+	self.UL = append(self.UL, UL)
+	self.UR = append(self.UR, UR)
+	self.ZL = append(self.ZL, ZL)
+	self.ZR = append(self.ZR, ZR)
+}
+
+func (self *ProofU) At(i uint64) (mcl.GT, mcl.GT, mcl.G1, mcl.G1, mcl.G1, mcl.G1) {
+	return self.TL[i], self.TR[i], self.UL[i], self.UR[i], self.ZL[i], self.ZR[i]
+}
+
+// CommitScalars is the commitment to b used by MIPP_u: U = sum b_i W_i.
+func CommitScalars(ck *cm.Ck, b []mcl.Fr) mcl.G1 {
+	var U mcl.G1
+	mcl.G1MulVec(&U, ck.W, b)
+	return U
+}
+
+// Powers returns 1, r, r^2, ..., r^{m-1}.
+func Powers(r mcl.Fr, m uint64) []mcl.Fr {
+	result := make([]mcl.Fr, m)
+	if m == 0 {
+		return result
+	}
+	result[0].SetInt64(1)
+	for i := uint64(1); i < m; i++ {
+		mcl.FrMul(&result[i], &result[i-1], &r)
+	}
+	return result
+}
+
+// FoldedExponent computes the final exponent after folding Powers(r, 2^l) with the l challenges.
+// Round k folds with (1 + c_k r^{M/2^{k+1}}), where c_k is the challenge or its inverse.
+func FoldedExponent(RandomChallenges []mcl.Fr, r mcl.Fr, invert bool) mcl.Fr {
+	var result mcl.Fr
+	var ONE mcl.Fr
+	l := len(RandomChallenges)
+
+	result.SetInt64(1)
+	ONE.SetInt64(1)
+	for i := 0; i < l; i++ {
+		var a, b mcl.Fr
+		a = utils.FrPow(r, int64(1)<<i)
+
+		if !invert {
+			b = RandomChallenges[l-i-1]
+		} else {
+			mcl.FrInv(&b, &RandomChallenges[l-i-1])
+		}
+
+		mcl.FrMul(&b, &b, &a)
+		mcl.FrAdd(&b, &b, &ONE)
+		mcl.FrMul(&result, &result, &b)
+	}
+	return result
+}
+
+// haloPoly is gipakzg.BuildHaloPoly, which also covers M = 1, where there are no challenges.
+func haloPoly(RandomChallenges []mcl.Fr, invert bool) []mcl.Fr {
+	if len(RandomChallenges) == 0 {
+		one := make([]mcl.Fr, 1)
+		one[0].SetInt64(1)
+		return one
+	}
+	return gipakzg.BuildHaloPoly(RandomChallenges, invert)
+}
+
+// statementHash binds the transcript to the statement before the first round.
+func statementHash(label string, T *mcl.GT, statement []byte) [32]byte {
+	data := make([]byte, 0)
+	data = append(data, []byte(label)...)
+	data = append(data, T.Serialize()...)
+	data = append(data, statement...)
+	return blake2b.Sum256(data)
+}
+
+// roundChallenge is H(Transcript, TL, TR, ZL, ZR). The transcript is updated in place.
+func roundChallenge(transcript *[32]byte, TL *mcl.GT, TR *mcl.GT, ZL []byte, ZR []byte) mcl.Fr {
+	var x mcl.Fr
+	data := make([]byte, 0)
+	data = append(data, transcript[:]...)
+	data = append(data, TL.Serialize()...)
+	data = append(data, TR.Serialize()...)
+	data = append(data, ZL...)
+	data = append(data, ZR...)
+	hash := blake2b.Sum256(data)
+	copy(transcript[:], hash[:])
+	x.SetHashOf(hash[:])
+	return x
+}
+
+// openingChallenge is H(Transcript, final value), the point at which the final key is opened.
+func openingChallenge(transcript *[32]byte, final []byte) mcl.Fr {
+	var x mcl.Fr
+	data := make([]byte, 0)
+	data = append(data, transcript[:]...)
+	data = append(data, final...)
+	hash := blake2b.Sum256(data)
+	copy(transcript[:], hash[:])
+	x.SetHashOf(hash[:])
+	return x
+}
@@ -0,0 +1,56 @@
+package mipp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+func testsetup() []uint8 {
+	return []uint8{8, 9, 10, 11, 12, 13, 14, 15, 16, 17}
+}
+
+func BenchmarkMIPP(b *testing.B) {
+
+	folderPath := "../ck-19"
+	rows := testsetup()
+	A, _ := utils.GenerateData(1 << (rows[len(rows)-1]))
+	var r mcl.Fr
+	r.Random()
+
+	for _, ell := range rows {
+		M := uint64(1) << ell
+		ck, _, kzg2 := cm.LoadKeys(M, folderPath)
+		_, verifier := AssembleProverVerifier(M, &ck, &kzg2, A[:M], r)
+		var proofs []Proof
+
+		b.Run(fmt.Sprintf("%d/Prove;%d", ell, M), func(b *testing.B) {
+			for bn := 0; bn < b.N; bn++ {
+				proverLocal := Prover{}
+				proverLocal.Init(M, &ck, &kzg2, A[:M], r)
+				b.StartTimer()
+				proof := proverLocal.Prove()
+				b.StopTimer()
+				proofs = append(proofs, proof)
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d/Verifier;%d", ell, M), func(b *testing.B) {
+			var proof Proof
+			for bn := 0; bn < b.N; bn++ {
+				verifierLocal := Verifier{}
+				verifierLocal.Init(M, &kzg2, verifier.T, verifier.C, r)
+				proof, proofs = proofs[0], proofs[1:]
+				b.StartTimer()
+				status := verifierLocal.Verify(proof)
+				b.StopTimer()
+				if !status {
+					b.Errorf("MIPP Verification failed")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package mipp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+func TestMIPP(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	for _, ell := range []uint8{1, 4, 7} {
+		M := uint64(1) << ell
+		alpha, beta, g, h := utils.RunMPC()
+		ck, kzg1, kzg2, A, B, r := GenerateMippInstance(M, alpha, beta, g, h)
+
+		prover, verifier := AssembleProverVerifier(M, ck, kzg2, A, r)
+		var C mcl.G1
+		mcl.G1MulVec(&C, A, Powers(r, M))
+		if !prover.C.IsEqual(&C) {
+			t.Fatalf("MIPP: Prover computed the wrong C")
+		}
+		proof := prover.Prove()
+		verifierCopy := verifier
+
+		t.Run(fmt.Sprintf("%d/MIPP;", M), func(t *testing.T) {
+			if !verifier.Verify(proof) {
+				t.Errorf("MIPP Test: Failed")
+			}
+		})
+
+		t.Run(fmt.Sprintf("%d/MIPPReject;", M), func(t *testing.T) {
+			// A different claimed C
+			var v Verifier
+			var wrongC mcl.G1
+			mcl.G1Add(&wrongC, &verifierCopy.C, &A[0])
+			v.Init(M, kzg2, verifierCopy.T, wrongC, r)
+			if v.Verify(proof) {
+				t.Errorf("MIPP Test: Wrong C accepted")
+			}
+			// A different r
+			var wrongR mcl.Fr
+			mcl.FrAdd(&wrongR, &r, &r)
+			v.Init(M, kzg2, verifierCopy.T, verifierCopy.C, wrongR)
+			if v.Verify(proof) {
+				t.Errorf("MIPP Test: Wrong r accepted")
+			}
+			// A tampered final value
+			tampered := proof
+			mcl.G1Add(&tampered.A, &tampered.A, &g)
+			v.Init(M, kzg2, verifierCopy.T, verifierCopy.C, r)
+			if v.Verify(tampered) {
+				t.Errorf("MIPP Test: Tampered proof accepted")
+			}
+		})
+
+		proverG2, verifierG2 := AssembleProverVerifierG2(M, ck, kzg1, B, r)
+		proofG2 := proverG2.Prove()
+		verifierG2Copy := verifierG2
+
+		t.Run(fmt.Sprintf("%d/MIPPG2;", M), func(t *testing.T) {
+			if !verifierG2.Verify(proofG2) {
+				t.Errorf("MIPP G2 Test: Failed")
+			}
+		})
+
+		t.Run(fmt.Sprintf("%d/MIPPG2Reject;", M), func(t *testing.T) {
+			var v VerifierG2
+			var wrongC mcl.G2
+			mcl.G2Add(&wrongC, &verifierG2Copy.C, &B[0])
+			v.Init(M, kzg1, verifierG2Copy.T, wrongC, r)
+			if v.Verify(proofG2) {
+				t.Errorf("MIPP G2 Test: Wrong C accepted")
+			}
+			if M > 1 {
+				tampered := proofG2
+				tampered.ZL = append([]mcl.G2{}, proofG2.ZL...)
+				mcl.G2Add(&tampered.ZL[0], &tampered.ZL[0], &h)
+				v.Init(M, kzg1, verifierG2Copy.T, verifierG2Copy.C, r)
+				if v.Verify(tampered) {
+					t.Errorf("MIPP G2 Test: Tampered proof accepted")
+				}
+			}
+		})
+	}
+}
+
+func TestMIPPU(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	for _, ell := range []uint8{1, 4, 7} {
+		M := uint64(1) << ell
+		alpha, beta, g, h := utils.RunMPC()
+		ck, kzg1, kzg2, A, _, _ := GenerateMippInstance(M, alpha, beta, g, h)
+		b := make([]mcl.Fr, M)
+		for i := range b {
+			b[i].Random()
+		}
+
+		prover, verifier := AssembleProverVerifierU(M, ck, kzg1, kzg2, A, b)
+		var C mcl.G1
+		mcl.G1MulVec(&C, A, b)
+		if !prover.C.IsEqual(&C) {
+			t.Fatalf("MIPP_u: Prover computed the wrong C")
+		}
+		T, U := prover.T, prover.U
+		proof := prover.Prove()
+
+		t.Run(fmt.Sprintf("%d/MIPPU;", M), func(t *testing.T) {
+			if !verifier.Verify(proof) {
+				t.Errorf("MIPP_u Test: Failed")
+			}
+		})
+
+		t.Run(fmt.Sprintf("%d/MIPPUReject;", M), func(t *testing.T) {
+			var v VerifierU
+			// C for different exponents
+			var wrongC mcl.G1
+			mcl.G1Add(&wrongC, &C, &A[0])
+			v.Init(M, kzg1, kzg2, T, U, wrongC)
+			if v.Verify(proof) {
+				t.Errorf("MIPP_u Test: Wrong C accepted")
+			}
+			// A commitment to different exponents
+			b[0].Random()
+			v.Init(M, kzg1, kzg2, T, CommitScalars(ck, b), C)
+			if v.Verify(proof) {
+				t.Errorf("MIPP_u Test: Wrong U accepted")
+			}
+			// A tampered final exponent
+			tampered := proof
+			mcl.FrAdd(&tampered.B, &tampered.B, &tampered.B)
+			v.Init(M, kzg1, kzg2, T, U, C)
+			if v.Verify(tampered) {
+				t.Errorf("MIPP_u Test: Tampered proof accepted")
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mipp
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Given alpha, beta, G, H, this will return ck, the KZG keys, A, B and r.
+func GenerateMippInstance(m uint64, alpha mcl.Fr, beta mcl.Fr, g mcl.G1, h mcl.G2) (*cm.Ck, *kzg.KZG1Settings, *kzg.KZG2Settings, []mcl.G1, []mcl.G2, mcl.Fr) {
+	ck, kzg1, kzg2 := cm.IPPSetupKZG(m, alpha, beta, g, h)
+	A, B := utils.GenerateData(m)
+	var r mcl.Fr
+	r.Random()
+	return ck, kzg1, kzg2, A, B, r
+}
+
+// Create a prover and a verifier for A in G1. The verifier is given the statement computed by the prover.
+func AssembleProverVerifier(m uint64, ck *cm.Ck, kzg2 *kzg.KZG2Settings, A []mcl.G1, r mcl.Fr) (Prover, Verifier) {
+
+	prover := Prover{}
+	verifier := Verifier{}
+
+	prover.Init(m, ck, kzg2, A, r)
+	verifier.Init(m, kzg2, prover.T, prover.C, r)
+	return prover, verifier
+}
+
+// Create a prover and a verifier for B in G2. The verifier is given the statement computed by the prover.
+func AssembleProverVerifierG2(m uint64, ck *cm.Ck, kzg1 *kzg.KZG1Settings, B []mcl.G2, r mcl.Fr) (ProverG2, VerifierG2) {
+
+	prover := ProverG2{}
+	verifier := VerifierG2{}
+
+	prover.Init(m, ck, kzg1, B, r)
+	verifier.Init(m, kzg1, prover.T, prover.C, r)
+	return prover, verifier
+}
+
+// Create a MIPP_u prover and verifier. The verifier is given the statement computed by the prover.
+func AssembleProverVerifierU(m uint64, ck *cm.Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, A []mcl.G1, b []mcl.Fr) (ProverU, VerifierU) {
+
+	prover := ProverU{}
+	verifier := VerifierU{}
+
+	prover.Init(m, ck, kzg1, kzg2, A, b)
+	verifier.Init(m, kzg1, kzg2, prover.T, prover.U, prover.C)
+	return prover, verifier
+}
@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+# Rewrites golden/testdata. Only run this after an intended change to the setup, transcript or encodings.
+#
+# Usage: scripts/gipa-golden.sh [rev]
+# Without rev, testdata is derived from the working tree.
+# With rev, testdata is derived from the commit rev in a temporary worktree, and the working tree
+# is then checked against it. Use the commit which introduced golden (or any earlier reference)
+# to pin later changes which must not alter the proofs.
+set -e
+shopt -s expand_aliases
+alias time='date; time'
+
+scriptdir=$(cd $(dirname $0); pwd -P)
+sourcedir=$(cd $scriptdir/..; pwd -P)
+
+cd $sourcedir
+if [ -z "$1" ]; then
+	time go test -v ./golden -run 'TestGolden$' -update
+	exit 0
+fi
+
+worktree=$(mktemp -d)
+trap "git worktree remove --force $worktree" EXIT
+git worktree add --detach $worktree $1
+(cd $worktree && time go test -v ./golden -run 'TestGolden$' -update)
+rm -rf golden/testdata
+cp -r $worktree/golden/testdata golden/testdata
+time go test -v ./golden -run 'TestGolden$'
@@ -0,0 +1,53 @@
+package snarkpack
+
+import (
+	"github.com/alinush/go-mcl"
+)
+
+// Groth16Proof is a Groth16 proof.
+type Groth16Proof struct {
+	A mcl.G1
+	B mcl.G2
+	C mcl.G1
+}
+
+// VerifyingKey is a Groth16 verifying key.
+// IC[0] is the constant term and IC[j+1] is the base for the j-th public input.
+type VerifyingKey struct {
+	Alpha mcl.G1
+	Beta  mcl.G2
+	Gamma mcl.G2
+	Delta mcl.G2
+	IC    []mcl.G1
+}
+
+// NumInputs returns the number of public inputs of a statement.
+func (self *VerifyingKey) NumInputs() int {
+	return len(self.IC) - 1
+}
+
+// PreparedInputs returns IC[0] + sum inputs[j] IC[j+1].
+func (self *VerifyingKey) PreparedInputs(inputs []mcl.Fr) mcl.G1 {
+	scalars := make([]mcl.Fr, len(self.IC))
+	scalars[0].SetInt64(1)
+	copy(scalars[1:], inputs)
+	var result mcl.G1
+	mcl.G1MulVec(&result, self.IC, scalars)
+	return result
+}
+
+// VerifyGroth16 checks e(A, B) == e(alpha, beta) e(PreparedInputs, gamma) e(C, delta).
+func VerifyGroth16(vk *VerifyingKey, proof *Groth16Proof, inputs []mcl.Fr) bool {
+	if len(inputs) != vk.NumInputs() {
+		return false
+	}
+	var negA mcl.G1
+	mcl.G1Neg(&negA, &proof.A)
+	P := []mcl.G1{negA, vk.Alpha, vk.PreparedInputs(inputs), proof.C}
+	Q := []mcl.G2{proof.B, vk.Beta, vk.Gamma, vk.Delta}
+
+	var result mcl.GT
+	mcl.MillerLoopVec(&result, P, Q)
+	mcl.FinalExp(&result, &result)
+	return result.IsOne()
+}
@@ -0,0 +1,89 @@
+package snarkpack
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/mipp"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Prover is a struct to manage the aggregator state.
+type Prover struct {
+	N      uint64
+	Ck     *cm.Ck
+	KZG1   *kzg.KZG1Settings
+	KZG2   *kzg.KZG2Settings
+	Proofs []Groth16Proof
+	Inputs [][]mcl.Fr
+}
+
+// Init takes n Groth16 proofs and their public inputs. n has to be a power of 2 and match the keys.
+func (self *Prover) Init(N uint64, ck *cm.Ck, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, proofs []Groth16Proof, inputs [][]mcl.Fr) {
+
+	utils.InstanceSizeChecker(N, "SnarkPack Prover Init: N is not a power of 2")
+	utils.SizeMismatchCheck(N, ck.M, "SnarkPack Prover Init: CK Size:")
+	utils.SizeMismatchCheck(N, uint64(len(proofs)), "SnarkPack Prover Init: Proofs Size:")
+	utils.SizeMismatchCheck(N, uint64(len(inputs)), "SnarkPack Prover Init: Inputs Size:")
+
+	*self = Prover{}
+	self.N = N
+	self.Ck = ck
+	self.KZG1 = kzg1
+	self.KZG2 = kzg2
+	self.Proofs = proofs
+	self.Inputs = inputs
+}
+
+// Aggregate computes the aggregate proof.
+// Parameters
+// ----------
+// None
+//
+// Returns
+// -------
+// Proof, the commitments, Z_C and the TIPP and MIPP proofs
+func (self *Prover) Aggregate() Proof {
+	var proof Proof
+
+	n := self.N
+	A := make([]mcl.G1, n)
+	B := make([]mcl.G2, n)
+	C := make([]mcl.G1, n)
+	for i := range self.Proofs {
+		A[i] = self.Proofs[i].A
+		B[i] = self.Proofs[i].B
+		C[i] = self.Proofs[i].C
+	}
+
+	TA := utils.InnerProd(A, self.Ck.V)
+	UB := utils.InnerProd(self.Ck.W, B)
+	proof.TC = utils.InnerProd(C, self.Ck.V)
+	r := Challenge(&TA, &UB, &proof.TC, self.Inputs)
+
+	// A_i^{r^i} with V_i^{r^{-i}} keeps <A, V> unchanged.
+	var rInv mcl.Fr
+	mcl.FrInv(&rInv, &r)
+	rPowers := mipp.Powers(r, n)
+	rInvPowers := mipp.Powers(rInv, n)
+	Ar := make([]mcl.G1, n)
+	ck := cm.Ck{M: n, V: make([]mcl.G2, n), W: self.Ck.W}
+	for i := uint64(0); i < n; i++ {
+		mcl.G1Mul(&Ar[i], &A[i], &rPowers[i])
+		mcl.G2Mul(&ck.V[i], &self.Ck.V[i], &rInvPowers[i])
+	}
+	proof.ComAB = cm.Com{Com: [3]mcl.GT{TA, UB, utils.InnerProd(Ar, B)}}
+
+	tipp := gipakzg.Prover{}
+	tipp.Init(n, &ck, self.KZG1, self.KZG2, Ar, B)
+	tipp.VScale = rInv
+	tipp.Transcript = tippTranscript(&r)
+	proof.TIPP = tipp.Prove()
+
+	mippProver := mipp.Prover{}
+	mippProver.Init(n, self.Ck, self.KZG2, C, r)
+	proof.ZC = mippProver.C
+	proof.MIPP = mippProver.Prove()
+	return proof
+}
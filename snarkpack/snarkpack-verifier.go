@@ -0,0 +1,89 @@
+package snarkpack
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/mipp"
+	"github.com/hyperproofs/gipa-go/utils"
+	"github.com/hyperproofs/kzg-go/kzg"
+)
+
+// Verifier is a struct to manage the verifier state.
+type Verifier struct {
+	N      uint64
+	KZG1   *kzg.KZG1Settings
+	KZG2   *kzg.KZG2Settings
+	VK     *VerifyingKey
+	Inputs [][]mcl.Fr
+}
+
+// Init takes the verifying key and the public inputs of the n aggregated proofs.
+func (self *Verifier) Init(N uint64, kzg1 *kzg.KZG1Settings, kzg2 *kzg.KZG2Settings, vk *VerifyingKey, inputs [][]mcl.Fr) {
+
+	utils.InstanceSizeChecker(N, "SnarkPack Verifier Init: N is not a power of 2")
+	utils.SizeMismatchCheck(2*N-1, uint64(len(kzg1.PK)), "SnarkPack Verifier Init: KZG1 PK Size:")
+	utils.SizeMismatchCheck(2*N-1, uint64(len(kzg2.PK)), "SnarkPack Verifier Init: KZG2 PK Size:")
+	utils.SizeMismatchCheck(N, uint64(len(inputs)), "SnarkPack Verifier Init: Inputs Size:")
+
+	*self = Verifier{}
+	self.N = N
+	self.KZG1 = kzg1
+	self.KZG2 = kzg2
+	self.VK = vk
+	self.Inputs = inputs
+}
+
+func (self *Verifier) Verify(proof Proof) bool {
+
+	for i := range self.Inputs {
+		if len(self.Inputs[i]) != self.VK.NumInputs() {
+			return false
+		}
+	}
+	r := Challenge(&proof.ComAB.Com[0], &proof.ComAB.Com[1], &proof.TC, self.Inputs)
+	var rInv mcl.Fr
+	mcl.FrInv(&rInv, &r)
+
+	tipp := gipakzg.Verifier{}
+	tipp.Init(self.N, self.KZG1, self.KZG2, proof.ComAB)
+	tipp.VScale = rInv
+	tipp.Transcript = tippTranscript(&r)
+	if !tipp.Verify(proof.TIPP) {
+		return false
+	}
+
+	mippVerifier := mipp.Verifier{}
+	mippVerifier.Init(self.N, self.KZG2, proof.TC, proof.ZC, r)
+	if !mippVerifier.Verify(proof.MIPP) {
+		return false
+	}
+	return self.Check(r, &proof.ComAB.Com[2], &proof.ZC)
+}
+
+// Check is a member function of Verifier
+// It checks Z_AB == e(alpha, beta)^{sum r^i} e(sum r^i PreparedInputs_i, gamma) e(Z_C, delta).
+func (self *Verifier) Check(r mcl.Fr, ZAB *mcl.GT, ZC *mcl.G1) bool {
+
+	// sum_i r^i inputs_i, with the constant term first.
+	scalars := make([]mcl.Fr, len(self.VK.IC))
+	scalars[0] = powerSum(r, self.N)
+	var base, temp mcl.Fr
+	base.SetInt64(1)
+	for i := range self.Inputs {
+		for j := range self.Inputs[i] {
+			mcl.FrMul(&temp, &self.Inputs[i][j], &base)
+			mcl.FrAdd(&scalars[j+1], &scalars[j+1], &temp)
+		}
+		mcl.FrMul(&base, &base, &r)
+	}
+	var IC, alpha mcl.G1
+	mcl.G1MulVec(&IC, self.VK.IC, scalars)
+	mcl.G1Mul(&alpha, &self.VK.Alpha, &scalars[0])
+
+	P := []mcl.G1{alpha, IC, *ZC}
+	Q := []mcl.G2{self.VK.Beta, self.VK.Gamma, self.VK.Delta}
+	var result mcl.GT
+	mcl.MillerLoopVec(&result, P, Q)
+	mcl.FinalExp(&result, &result)
+	return result.IsEqual(ZAB)
+}
@@ -0,0 +1,65 @@
+package snarkpack
+
+import (
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/gipakzg"
+	"github.com/hyperproofs/gipa-go/mipp"
+	"github.com/hyperproofs/gipa-go/utils"
+	"golang.org/x/crypto/blake2b"
+)
+
+// Aggregation of n Groth16 proofs for the same verifying key (SnarkPack, https://eprint.iacr.org/2021/529).
+// The prover commits to A and B with cm.IPPCM, to C with T_C = <C, V> and derives r from the commitments and
+// the public inputs. It then shows
+//   Z_AB = prod e(A_i, B_i)^{r^i}   with TIPP, i.e. gipakzg on (r^i A_i, B_i) and the rescaled keys V_i^{r^{-i}}
+//   Z_C  = sum r^i C_i              with MIPP, see mipp.Prover
+// and the verifier checks Z_AB == e(alpha, beta)^{sum r^i} e(sum r^i PreparedInputs_i, gamma) e(Z_C, delta).
+// Apart from reading the public inputs, the verifier runs in O(log n).
+
+// Proof is an aggregate of n Groth16 proofs.
+type Proof struct {
+	ComAB cm.Com // (<A, V>, <W, B>, Z_AB)
+	TC    mcl.GT // <C, V>
+	ZC    mcl.G1 // sum r^i C_i
+	TIPP  gipakzg.Proof
+	MIPP  mipp.Proof
+}
+
+// Challenge returns r = Hash(<A, V> || <W, B> || <C, V> || inputs).
+func Challenge(TA *mcl.GT, UB *mcl.GT, TC *mcl.GT, inputs [][]mcl.Fr) mcl.Fr {
+	hasher, _ := blake2b.New256(nil)
+	hasher.Write([]byte("SnarkPack"))
+	hasher.Write(TA.Serialize())
+	hasher.Write(UB.Serialize())
+	hasher.Write(TC.Serialize())
+	for i := range inputs {
+		for j := range inputs[i] {
+			hasher.Write(inputs[i][j].Serialize())
+		}
+	}
+	var r mcl.Fr
+	r.SetHashOf(hasher.Sum(nil))
+	return r
+}
+
+// tippTranscript binds the TIPP transcript to r, as gipakzg starts from an empty transcript.
+func tippTranscript(r *mcl.Fr) [32]byte {
+	return blake2b.Sum256(append([]byte("SnarkPack TIPP"), r.Serialize()...))
+}
+
+// powerSum returns sum_{i < n} r^i.
+func powerSum(r mcl.Fr, n uint64) mcl.Fr {
+	var result mcl.Fr
+	if r.IsOne() {
+		result.SetInt64(int64(n))
+		return result
+	}
+	var num, den, one mcl.Fr
+	one.SetInt64(1)
+	rn := utils.FrPow(r, int64(n))
+	mcl.FrSub(&num, &rn, &one)
+	mcl.FrSub(&den, &r, &one)
+	mcl.FrDiv(&result, &num, &den)
+	return result
+}
@@ -0,0 +1,72 @@
+package snarkpack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alinush/go-mcl"
+	"github.com/hyperproofs/gipa-go/cm"
+	"github.com/hyperproofs/gipa-go/utils"
+)
+
+func TestSnarkPack(t *testing.T) {
+	mcl.InitFromString("bls12-381")
+
+	for _, ell := range []uint8{1, 3, 6} {
+		N := uint64(1) << ell
+		alpha, beta, g, h := utils.RunMPC()
+		ck, kzg1, kzg2 := cm.IPPSetupKZG(N, alpha, beta, g, h)
+		vk, proofs, inputs := GenerateSyntheticProofs(N, 3, g, h)
+
+		t.Run(fmt.Sprintf("%d/Groth16;", N), func(t *testing.T) {
+			for i := range proofs {
+				if !VerifyGroth16(vk, &proofs[i], inputs[i]) {
+					t.Fatalf("Groth16 Test: Synthetic proof %d rejected", i)
+				}
+			}
+		})
+
+		prover := Prover{}
+		prover.Init(N, ck, kzg1, kzg2, proofs, inputs)
+		proof := prover.Aggregate()
+
+		t.Run(fmt.Sprintf("%d/SnarkPack;", N), func(t *testing.T) {
+			verifier := Verifier{}
+			verifier.Init(N, kzg1, kzg2, vk, inputs)
+			if !verifier.Verify(proof) {
+				t.Errorf("SnarkPack Test: Failed")
+			}
+		})
+
+		t.Run(fmt.Sprintf("%d/SnarkPackReject;", N), func(t *testing.T) {
+			verifier := Verifier{}
+
+			// Different public inputs
+			wrongInputs := make([][]mcl.Fr, N)
+			copy(wrongInputs, inputs)
+			wrongInputs[N-1] = append([]mcl.Fr{}, inputs[N-1]...)
+			mcl.FrAdd(&wrongInputs[N-1][0], &wrongInputs[N-1][0], &alpha)
+			verifier.Init(N, kzg1, kzg2, vk, wrongInputs)
+			if verifier.Verify(proof) {
+				t.Errorf("SnarkPack Test: Wrong inputs accepted")
+			}
+
+			// An invalid Groth16 proof in the batch
+			badProofs := append([]Groth16Proof{}, proofs...)
+			mcl.G1Add(&badProofs[0].C, &badProofs[0].C, &g)
+			prover.Init(N, ck, kzg1, kzg2, badProofs, inputs)
+			badProof := prover.Aggregate()
+			verifier.Init(N, kzg1, kzg2, vk, inputs)
+			if verifier.Verify(badProof) {
+				t.Errorf("SnarkPack Test: Invalid proof accepted")
+			}
+
+			// A tampered Z_C
+			tampered := proof
+			mcl.G1Add(&tampered.ZC, &tampered.ZC, &g)
+			if verifier.Verify(tampered) {
+				t.Errorf("SnarkPack Test: Tampered Z_C accepted")
+			}
+		})
+	}
+}
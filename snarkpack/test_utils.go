@@ -0,0 +1,60 @@
+package snarkpack
+
+import (
+	"github.com/alinush/go-mcl"
+)
+
+// GenerateSyntheticProofs returns a verifying key and n valid Groth16 proofs with random public inputs.
+// There is no circuit: the trapdoor of the key is known and C is solved from
+// a b = alpha beta + ic gamma + c delta, where ic is the discrete log of the prepared inputs.
+func GenerateSyntheticProofs(n uint64, nInputs int, g mcl.G1, h mcl.G2) (*VerifyingKey, []Groth16Proof, [][]mcl.Fr) {
+
+	var alpha, beta, gamma, delta, deltaInv mcl.Fr
+	alpha.Random()
+	beta.Random()
+	gamma.Random()
+	delta.Random()
+	mcl.FrInv(&deltaInv, &delta)
+
+	vk := new(VerifyingKey)
+	mcl.G1Mul(&vk.Alpha, &g, &alpha)
+	mcl.G2Mul(&vk.Beta, &h, &beta)
+	mcl.G2Mul(&vk.Gamma, &h, &gamma)
+	mcl.G2Mul(&vk.Delta, &h, &delta)
+	u := make([]mcl.Fr, nInputs+1)
+	vk.IC = make([]mcl.G1, nInputs+1)
+	for j := range u {
+		u[j].Random()
+		mcl.G1Mul(&vk.IC[j], &g, &u[j])
+	}
+
+	var alphaBeta mcl.Fr
+	mcl.FrMul(&alphaBeta, &alpha, &beta)
+
+	proofs := make([]Groth16Proof, n)
+	inputs := make([][]mcl.Fr, n)
+	for i := range proofs {
+		inputs[i] = make([]mcl.Fr, nInputs)
+		ic := u[0]
+		var temp mcl.Fr
+		for j := range inputs[i] {
+			inputs[i][j].Random()
+			mcl.FrMul(&temp, &inputs[i][j], &u[j+1])
+			mcl.FrAdd(&ic, &ic, &temp)
+		}
+
+		var a, b, c mcl.Fr
+		a.Random()
+		b.Random()
+		mcl.FrMul(&c, &a, &b)
+		mcl.FrSub(&c, &c, &alphaBeta)
+		mcl.FrMul(&temp, &ic, &gamma)
+		mcl.FrSub(&c, &c, &temp)
+		mcl.FrMul(&c, &c, &deltaInv)
+
+		mcl.G1Mul(&proofs[i].A, &g, &a)
+		mcl.G2Mul(&proofs[i].B, &h, &b)
+		mcl.G1Mul(&proofs[i].C, &g, &c)
+	}
+	return vk, proofs, inputs
+}
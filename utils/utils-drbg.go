@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/alinush/go-mcl"
+	"golang.org/x/crypto/blake2b"
+)
+
+// DRBG is a deterministic random bit generator: block i is BLAKE2b-512(key, i) where key is derived from a seed string.
+// It makes instances reproducible across runs for tests, benchmarks and golden vectors.
+// Never use it for keys which protect anything.
+type DRBG struct {
+	key     [32]byte
+	counter uint64
+	buf     []byte
+}
+
+func NewDRBG(seed string) *DRBG {
+	self := &DRBG{}
+	self.key = blake2b.Sum256([]byte("gipa-go DRBG " + seed))
+	return self
+}
+
+// Read fills p with the next bytes of the stream. It never fails.
+func (self *DRBG) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(self.buf) == 0 {
+			self.refill()
+		}
+		c := copy(p[n:], self.buf)
+		self.buf = self.buf[c:]
+		n += c
+	}
+	return n, nil
+}
+
+func (self *DRBG) refill() {
+	h, _ := blake2b.New512(self.key[:])
+	var counter [8]byte
+	binary.LittleEndian.PutUint64(counter[:], self.counter)
+	h.Write(counter[:])
+	self.buf = h.Sum(nil)
+	self.counter++
+}
+
+// Fr reduces 64 bytes of the stream, thus the bias is negligible.
+func (self *DRBG) Fr() mcl.Fr {
+	var x mcl.Fr
+	data := make([]byte, 64)
+	self.Read(data)
+	if err := x.SetLittleEndianMod(data); err != nil {
+		panic(fmt.Sprintf("DRBG: %v", err))
+	}
+	return x
+}
+
+// G1 hashes bytes of the stream to the group, like mcl.G1.Random.
+func (self *DRBG) G1() mcl.G1 {
+	var a mcl.G1
+	data := make([]byte, GetFrByteSize())
+	self.Read(data)
+	if err := a.HashAndMapTo(data); err != nil {
+		panic(fmt.Sprintf("DRBG: %v", err))
+	}
+	return a
+}
+
+// G2 hashes bytes of the stream to the group, like mcl.G2.Random.
+func (self *DRBG) G2() mcl.G2 {
+	var b mcl.G2
+	data := make([]byte, GetFrByteSize())
+	self.Read(data)
+	if err := b.HashAndMapTo(data); err != nil {
+		panic(fmt.Sprintf("DRBG: %v", err))
+	}
+	return b
+}
+
+// sampler is the source of randomness of the instance generators.
+type sampler interface {
+	Fr() mcl.Fr
+	G1() mcl.G1
+	G2() mcl.G2
+}
+
+// csprng samples with mcl's Random.
+type csprng struct{}
+
+func (csprng) Fr() mcl.Fr {
+	var x mcl.Fr
+	x.Random()
+	return x
+}
+
+func (csprng) G1() mcl.G1 {
+	var a mcl.G1
+	a.Random()
+	return a
+}
+
+func (csprng) G2() mcl.G2 {
+	var b mcl.G2
+	b.Random()
+	return b
+}
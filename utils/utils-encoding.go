@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Wire format shared by every proof type.
+// Header layout (11 bytes):
+// magic (4) | version (1) | curve id (1) | proof type (1) | rounds (4, little endian)
+// The header is followed by fixed size group elements, see GetG1ByteSize, GetG2ByteSize and GetGTByteSize.
+const (
+	WireVersion    = 1
+	WireHeaderSize = 11
+	// Same value as mcl.BLS12_381. This code base only runs on BLS12-381.
+	CurveBLS12_381 = 5
+	// M is a uint64, thus there cannot be more than 63 rounds of GIPA.
+	MaxRounds = 63
+)
+
+// Proof type tags written in the header
+const (
+	ProofTypeGipa       = 1
+	ProofTypeGipaKzg    = 2
+	ProofTypeBatch      = 3
+	ProofTypeBatchPlain = 4
+	ProofTypeIpa        = 5
+	ProofTypeKnownB     = 6
+)
+
+var WireMagic = [4]byte{'G', 'I', 'P', 'A'}
+
+var (
+	ErrTruncated     = errors.New("wire: truncated input")
+	ErrTrailingBytes = errors.New("wire: trailing bytes after proof")
+)
+
+// WriteWireHeader writes the header for a proof of type proofType with rounds rounds.
+func WriteWireHeader(w io.Writer, proofType uint8, rounds int) error {
+
+	if rounds < 0 || rounds > MaxRounds {
+		return fmt.Errorf("wire: invalid number of rounds: %d", rounds)
+	}
+	header := make([]byte, WireHeaderSize)
+	copy(header, WireMagic[:])
+	header[4] = WireVersion
+	header[5] = CurveBLS12_381
+	header[6] = proofType
+	binary.LittleEndian.PutUint32(header[7:], uint32(rounds))
+	_, err := w.Write(header)
+	return err
+}
+
+// ReadWireHeader reads and validates the header. It fails if the proof type does not match proofType.
+// Returns the number of rounds recorded in the header.
+func ReadWireHeader(r io.Reader, proofType uint8) (int, error) {
+
+	header := make([]byte, WireHeaderSize)
+	if err := readFull(r, header, "header"); err != nil {
+		return 0, err
+	}
+	if header[0] != WireMagic[0] || header[1] != WireMagic[1] || header[2] != WireMagic[2] || header[3] != WireMagic[3] {
+		return 0, fmt.Errorf("wire: bad magic %x", header[:4])
+	}
+	if header[4] != WireVersion {
+		return 0, fmt.Errorf("wire: unsupported version %d", header[4])
+	}
+	if header[5] != CurveBLS12_381 {
+		return 0, fmt.Errorf("wire: unsupported curve id %d", header[5])
+	}
+	if header[6] != proofType {
+		return 0, fmt.Errorf("wire: proof type is %d, expected %d", header[6], proofType)
+	}
+	rounds := binary.LittleEndian.Uint32(header[7:])
+	if rounds > MaxRounds {
+		return 0, fmt.Errorf("wire: invalid number of rounds: %d", rounds)
+	}
+	return int(rounds), nil
+}
+
+func readFull(r io.Reader, buf []byte, what string) error {
+	_, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: reading %s", ErrTruncated, what)
+	}
+	return err
+}
+
+func WriteG1(w io.Writer, a *mcl.G1) error {
+	_, err := w.Write(a.Serialize())
+	return err
+}
+
+func WriteG2(w io.Writer, b *mcl.G2) error {
+	_, err := w.Write(b.Serialize())
+	return err
+}
+
+func WriteGT(w io.Writer, t *mcl.GT) error {
+	_, err := w.Write(t.Serialize())
+	return err
+}
+
+func WriteFr(w io.Writer, x *mcl.Fr) error {
+	_, err := w.Write(x.Serialize())
+	return err
+}
+
+func ReadG1(r io.Reader, a *mcl.G1) error {
+	data := make([]byte, GetG1ByteSize())
+	if err := readFull(r, data, "G1"); err != nil {
+		return err
+	}
+	if err := a.Deserialize(data); err != nil {
+		return fmt.Errorf("wire: invalid G1: %w", err)
+	}
+	return nil
+}
+
+func ReadG2(r io.Reader, b *mcl.G2) error {
+	data := make([]byte, GetG2ByteSize())
+	if err := readFull(r, data, "G2"); err != nil {
+		return err
+	}
+	if err := b.Deserialize(data); err != nil {
+		return fmt.Errorf("wire: invalid G2: %w", err)
+	}
+	return nil
+}
+
+// ReadGT fails for Fp12 elements outside GT, see GTIsValid.
+func ReadGT(r io.Reader, t *mcl.GT) error {
+	data := make([]byte, GetGTByteSize())
+	if err := readFull(r, data, "GT"); err != nil {
+		return err
+	}
+	if err := t.Deserialize(data); err != nil {
+		return fmt.Errorf("wire: invalid GT: %w", err)
+	}
+	if !GTIsValid(t) {
+		return fmt.Errorf("wire: invalid GT: %w", ErrNotInGT)
+	}
+	return nil
+}
+
+func ReadFr(r io.Reader, x *mcl.Fr) error {
+	data := make([]byte, GetFrByteSize())
+	if err := readFull(r, data, "Fr"); err != nil {
+		return err
+	}
+	if err := x.Deserialize(data); err != nil {
+		return fmt.Errorf("wire: invalid Fr: %w", err)
+	}
+	return nil
+}
+
+// CountingReader wraps an io.Reader and counts the bytes read through it.
+// Used by the ReadFrom implementations of the proofs.
+type CountingReader struct {
+	R io.Reader
+	N int64
+}
+
+func (self *CountingReader) Read(p []byte) (int, error) {
+	n, err := self.R.Read(p)
+	self.N += int64(n)
+	return n, err
+}
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"unsafe"
+
+	"github.com/alinush/go-mcl"
+)
+
+// GT is Fp12 = Fp6[w]/(w^2 - v), Fp6 = Fp2[v]/(v^3 - xi), xi = 1 + i.
+// mcl stores it as six Fp2 coefficients: c0.c0, c0.c1, c0.c2, c1.c0, c1.c1, c1.c2.
+func gtAsFp2(x *mcl.GT) *[6]mcl.Fp2 {
+	return (*[6]mcl.Fp2)(unsafe.Pointer(x))
+}
+
+// fp2MulByNonresidue computes out = xi * x = (1 + i) x
+func fp2MulByNonresidue(out *mcl.Fp2, x *mcl.Fp2) {
+	var a, b mcl.Fp
+	mcl.FpSub(&a, &x.D[0], &x.D[1])
+	mcl.FpAdd(&b, &x.D[0], &x.D[1])
+	out.D[0] = a
+	out.D[1] = b
+}
+
+// fp4Sqr computes (c0 + c1 s) = (a + b s)^2 in Fp4 = Fp2[s]/(s^2 - xi)
+func fp4Sqr(c0 *mcl.Fp2, c1 *mcl.Fp2, a *mcl.Fp2, b *mcl.Fp2) {
+	var t0, t1, t2 mcl.Fp2
+	mcl.Fp2Sqr(&t0, a)
+	mcl.Fp2Sqr(&t1, b)
+	fp2MulByNonresidue(&t2, &t1)
+	mcl.Fp2Add(c0, &t2, &t0)
+	mcl.Fp2Add(&t2, a, b)
+	mcl.Fp2Sqr(&t2, &t2)
+	mcl.Fp2Sub(&t2, &t2, &t0)
+	mcl.Fp2Sub(c1, &t2, &t1)
+}
+
+// GTCyclotomicSqr computes out = x^2 with the squaring of Granger and Scott.
+// It only holds in the cyclotomic subgroup, which contains GT. Like mcl.GTPow, x has to be in GT.
+// Viewing x as A + B w + C w^2 over Fp4 = Fp2[w^3]:
+// A' = 3 A^2 - 2 conj(A), B' = 3 xi C^2 + 2 conj(B), C' = 3 B^2 - 2 conj(C)
+func GTCyclotomicSqr(out *mcl.GT, x *mcl.GT) {
+
+	f := gtAsFp2(x)
+	z0, z4, z3 := f[0], f[1], f[2]
+	z2, z1, z5 := f[3], f[4], f[5]
+	var t0, t1, t2, t3 mcl.Fp2
+
+	// A = z0 + z1 s
+	fp4Sqr(&t0, &t1, &z0, &z1)
+	mcl.Fp2Sub(&z0, &t0, &z0)
+	mcl.Fp2Add(&z0, &z0, &z0)
+	mcl.Fp2Add(&z0, &z0, &t0)
+	mcl.Fp2Add(&z1, &t1, &z1)
+	mcl.Fp2Add(&z1, &z1, &z1)
+	mcl.Fp2Add(&z1, &z1, &t1)
+
+	// B = z2 + z3 s and C = z4 + z5 s
+	fp4Sqr(&t0, &t1, &z2, &z3)
+	fp4Sqr(&t2, &t3, &z4, &z5)
+
+	mcl.Fp2Sub(&z4, &t0, &z4)
+	mcl.Fp2Add(&z4, &z4, &z4)
+	mcl.Fp2Add(&z4, &z4, &t0)
+	mcl.Fp2Add(&z5, &t1, &z5)
+	mcl.Fp2Add(&z5, &z5, &z5)
+	mcl.Fp2Add(&z5, &z5, &t1)
+
+	fp2MulByNonresidue(&t0, &t3)
+	mcl.Fp2Add(&z2, &t0, &z2)
+	mcl.Fp2Add(&z2, &z2, &z2)
+	mcl.Fp2Add(&z2, &z2, &t0)
+	mcl.Fp2Sub(&z3, &t2, &z3)
+	mcl.Fp2Add(&z3, &z3, &z3)
+	mcl.Fp2Add(&z3, &z3, &t2)
+
+	r := gtAsFp2(out)
+	r[0], r[1], r[2] = z0, z4, z3
+	r[3], r[4], r[5] = z2, z1, z5
+}
+
+// ErrNotInGT is returned by ReadGT and GTFromHex for Fp12 elements outside GT.
+var ErrNotInGT = errors.New("not in GT")
+
+// GTIsValid returns true if x is in GT, the subgroup of order r of Fp12.
+// It computes x^r with generic squarings, which also hold outside the cyclotomic subgroup.
+func GTIsValid(x *mcl.GT) bool {
+
+	var r big.Int
+	r.SetString(mcl.GetCurveOrder(), 10)
+	var result mcl.GT
+	result.SetInt64(1)
+	for i := r.BitLen() - 1; i >= 0; i-- {
+		gtSqr(&result, &result)
+		if r.Bit(i) == 1 {
+			mcl.GTMul(&result, &result, x)
+		}
+	}
+	return result.IsOne()
+}
+
+// gtWindow is the window size of GTMultiExp.
+const gtWindow = 4
+
+// GTMultiExp computes prod bases[i]^exps[i] with interleaved fixed windows (Straus).
+// All the exponents share the squarings. They are generic Fp12 squarings, thus the result is
+// well defined for any bases in Fp12.
+func GTMultiExp(bases []mcl.GT, exps []mcl.Fr) mcl.GT {
+	return gtMultiExp(bases, exps, gtSqr)
+}
+
+// GTMultiExpCyclotomic is GTMultiExp with cyclotomic squarings, see GTCyclotomicSqr.
+// The bases have to be in GT, e.g. outputs of a pairing or elements decoded with ReadGT or GTFromHex.
+// Otherwise the result is meaningless.
+func GTMultiExpCyclotomic(bases []mcl.GT, exps []mcl.Fr) mcl.GT {
+	return gtMultiExp(bases, exps, GTCyclotomicSqr)
+}
+
+func gtSqr(out *mcl.GT, x *mcl.GT) {
+	mcl.GTMul(out, x, x)
+}
+
+func gtMultiExp(bases []mcl.GT, exps []mcl.Fr, sqr func(out *mcl.GT, x *mcl.GT)) mcl.GT {
+
+	n := len(bases)
+	if n != len(exps) {
+		panic(fmt.Sprintf("GTMultiExp: Error %d %d", n, len(exps)))
+	}
+	var result mcl.GT
+	result.SetInt64(1)
+	if n == 0 {
+		return result
+	}
+
+	// table[i][d] = bases[i]^d
+	table := make([][1 << gtWindow]mcl.GT, n)
+	k := make([]big.Int, n)
+	maxBits := 0
+	for i := range bases {
+		table[i][0].SetInt64(1)
+		table[i][1] = bases[i]
+		for d := 2; d < 1<<gtWindow; d++ {
+			mcl.GTMul(&table[i][d], &table[i][d-1], &bases[i])
+		}
+		k[i].SetString(exps[i].GetString(16), 16)
+		if k[i].BitLen() > maxBits {
+			maxBits = k[i].BitLen()
+		}
+	}
+
+	windows := (maxBits + gtWindow - 1) / gtWindow
+	for j := windows - 1; j >= 0; j-- {
+		for s := 0; s < gtWindow && j != windows-1; s++ {
+			sqr(&result, &result)
+		}
+		for i := range bases {
+			d := 0
+			for b := 0; b < gtWindow; b++ {
+				d |= int(k[i].Bit(j*gtWindow+b)) << b
+			}
+			if d != 0 {
+				mcl.GTMul(&result, &result, &table[i][d])
+			}
+		}
+	}
+	return result
+}
+
+// GTFoldAll returns C[j] prod_k L[k][j]^(x_k) R[k][j]^(x_k^-1) for every component j, which is C after
+// the rounds com' = L^x com R^(x^-1). L[k] and R[k] hold the components of round k, as C.
+// Each component is one GTMultiExpCyclotomic, thus L and R have to be in GT.
+func GTFoldAll(C []mcl.GT, L [][]mcl.GT, R [][]mcl.GT, X []mcl.Fr) []mcl.GT {
+
+	l := len(X)
+	if len(L) != l || len(R) != l {
+		panic(fmt.Sprintf("GTFoldAll: Error %d %d %d", len(L), len(R), l))
+	}
+	exps := make([]mcl.Fr, 2*l)
+	for k := range X {
+		if len(L[k]) != len(C) || len(R[k]) != len(C) {
+			panic(fmt.Sprintf("GTFoldAll: Error %d %d %d", len(L[k]), len(R[k]), len(C)))
+		}
+		exps[2*k] = X[k]
+		mcl.FrInv(&exps[2*k+1], &X[k])
+	}
+
+	result := make([]mcl.GT, len(C))
+	bases := make([]mcl.GT, 2*l)
+	for j := range C {
+		for k := range X {
+			bases[2*k] = L[k][j]
+			bases[2*k+1] = R[k][j]
+		}
+		folded := GTMultiExpCyclotomic(bases, exps)
+		mcl.GTMul(&result[j], &C[j], &folded)
+	}
+	return result
+}
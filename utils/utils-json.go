@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/alinush/go-mcl"
+)
+
+// Human readable (JSON) form of the wire format.
+// Group elements are hex strings of the same bytes that the binary encoding uses.
+
+var proofTypeNames = map[uint8]string{
+	ProofTypeGipa:       "gipa",
+	ProofTypeGipaKzg:    "gipakzg",
+	ProofTypeBatch:      "batch",
+	ProofTypeBatchPlain: "batchplain",
+	ProofTypeIpa:        "ipa",
+	ProofTypeKnownB:     "knownb",
+}
+
+// WireHeaderJSON is the JSON counterpart of the binary header.
+// The number of rounds is implied by the length of the rounds array.
+type WireHeaderJSON struct {
+	Version uint8  `json:"Version"`
+	Curve   string `json:"Curve"`
+	Type    string `json:"Type"`
+}
+
+func NewWireHeaderJSON(proofType uint8) WireHeaderJSON {
+	return WireHeaderJSON{WireVersion, "bls12-381", proofTypeNames[proofType]}
+}
+
+// Check validates the header against the expected proof type.
+func (self *WireHeaderJSON) Check(proofType uint8) error {
+	if self.Version != WireVersion {
+		return fmt.Errorf("json: unsupported version %d", self.Version)
+	}
+	if self.Curve != "bls12-381" {
+		return fmt.Errorf("json: unsupported curve %q", self.Curve)
+	}
+	if self.Type != proofTypeNames[proofType] {
+		return fmt.Errorf("json: proof type is %q, expected %q", self.Type, proofTypeNames[proofType])
+	}
+	return nil
+}
+
+func G1ToHex(a *mcl.G1) string {
+	return hex.EncodeToString(a.Serialize())
+}
+
+func G2ToHex(b *mcl.G2) string {
+	return hex.EncodeToString(b.Serialize())
+}
+
+func GTToHex(t *mcl.GT) string {
+	return hex.EncodeToString(t.Serialize())
+}
+
+func FrToHex(x *mcl.Fr) string {
+	return hex.EncodeToString(x.Serialize())
+}
+
+func decodeHex(s string, size int, what string) ([]byte, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("json: %s: %w", what, err)
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("json: %s: expected %d bytes, got %d", what, size, len(data))
+	}
+	return data, nil
+}
+
+func G1FromHex(a *mcl.G1, s string) error {
+	data, err := decodeHex(s, GetG1ByteSize(), "G1")
+	if err != nil {
+		return err
+	}
+	if err := a.Deserialize(data); err != nil {
+		return fmt.Errorf("json: invalid G1: %w", err)
+	}
+	return nil
+}
+
+func G2FromHex(b *mcl.G2, s string) error {
+	data, err := decodeHex(s, GetG2ByteSize(), "G2")
+	if err != nil {
+		return err
+	}
+	if err := b.Deserialize(data); err != nil {
+		return fmt.Errorf("json: invalid G2: %w", err)
+	}
+	return nil
+}
+
+// GTFromHex fails for Fp12 elements outside GT, see GTIsValid.
+func GTFromHex(t *mcl.GT, s string) error {
+	data, err := decodeHex(s, GetGTByteSize(), "GT")
+	if err != nil {
+		return err
+	}
+	if err := t.Deserialize(data); err != nil {
+		return fmt.Errorf("json: invalid GT: %w", err)
+	}
+	if !GTIsValid(t) {
+		return fmt.Errorf("json: invalid GT: %w", ErrNotInGT)
+	}
+	return nil
+}
+
+func FrFromHex(x *mcl.Fr, s string) error {
+	data, err := decodeHex(s, GetFrByteSize(), "Fr")
+	if err != nil {
+		return err
+	}
+	if err := x.Deserialize(data); err != nil {
+		return fmt.Errorf("json: invalid Fr: %w", err)
+	}
+	return nil
+}
@@ -43,28 +43,35 @@ func G2SliceIsEqual(a, b []mcl.G2) bool {
 // vec1 and vec2 has to be same size
 // Parameters
 // ----------
-// result: G1 slice where the results are stored
 // x : Fr, the exponent
 // vec1: slice of mcl.G1
 // vec2: slice of mcl.G1
 // Returns
 // -------
+// A new slice with the result. Use G1FoldInto to fold into an existing slice.
 func G1Fold(x mcl.Fr, vec1 []mcl.G1, vec2 []mcl.G1) []mcl.G1 {
 	// Vec1^x . Vec2
-	// var result []mcl.G1
+	result := make([]mcl.G1, len(vec1))
+	G1FoldInto(result, x, vec1, vec2, 0)
+	return result
+}
+
+// G1FoldInto computes result = x * vec1 + vec2 element wise without allocating.
+// result may be vec1 or vec2, thus folding A into A_L in place is G1FoldInto(A_L, x, A_R, A_L, workers).
+// The scalar multiplications are split among workers goroutines. With workers == 0, see FoldThreshold.
+func G1FoldInto(result []mcl.G1, x mcl.Fr, vec1 []mcl.G1, vec2 []mcl.G1, workers int) {
+
 	m := len(vec1)
-	if m != len(vec2) {
+	if m != len(vec2) || m != len(result) {
 		panic("G1: Fold: Error")
 	}
-
-	result := make([]mcl.G1, m)
-
-	for i := range vec1 {
+	ParallelFor(foldWorkers(workers, m), m, func(start int, stop int) {
 		var temp mcl.G1
-		mcl.G1Mul(&temp, &vec1[i], &x)
-		mcl.G1Add(&result[i], &temp, &vec2[i])
-	}
-	return result
+		for i := start; i < stop; i++ {
+			mcl.G1Mul(&temp, &vec1[i], &x)
+			mcl.G1Add(&result[i], &temp, &vec2[i])
+		}
+	})
 }
 
 // G2Fold performs element wise: result = x * vec1 + vec2
@@ -72,28 +79,70 @@ func G1Fold(x mcl.Fr, vec1 []mcl.G1, vec2 []mcl.G1) []mcl.G1 {
 // vec1 and vec2 has to be same size
 // Parameters
 // ----------
-// result: G2 slice where the results are stored
 // x : Fr, the exponent
 // vec1: slice of mcl.G2
 // vec2: slice of mcl.G2
 // Returns
 // -------
-// None. Call by reference, thus output is stored in variable result
+// A new slice with the result. Use G2FoldInto to fold into an existing slice.
 func G2Fold(x mcl.Fr, vec1 []mcl.G2, vec2 []mcl.G2) []mcl.G2 {
-	// Not sure why call by reference did not work. Hence returning explicitly.
 	// Vec1^x . Vec2
-	// var result []mcl.G2
+	result := make([]mcl.G2, len(vec1))
+	G2FoldInto(result, x, vec1, vec2, 0)
+	return result
+}
+
+// G2FoldInto computes result = x * vec1 + vec2 element wise without allocating, see G1FoldInto.
+func G2FoldInto(result []mcl.G2, x mcl.Fr, vec1 []mcl.G2, vec2 []mcl.G2, workers int) {
+
 	m := len(vec1)
-	if m != len(vec2) {
-		// Error handling
+	if m != len(vec2) || m != len(result) {
 		panic("G2: Fold: Error")
 	}
-	result := make([]mcl.G2, m)
+	ParallelFor(foldWorkers(workers, m), m, func(start int, stop int) {
+		var temp mcl.G2
+		for i := start; i < stop; i++ {
+			mcl.G2Mul(&temp, &vec1[i], &x)
+			mcl.G2Add(&result[i], &temp, &vec2[i])
+		}
+	})
+}
+
+// FrFold performs element wise: result = x * vec1 + vec2
+// vec1 and vec2 has to be same size
+func FrFold(x mcl.Fr, vec1 []mcl.Fr, vec2 []mcl.Fr) []mcl.Fr {
+	m := len(vec1)
+	if m != len(vec2) {
+		panic("Fr: Fold: Error")
+	}
+	result := make([]mcl.Fr, m)
 
 	for i := range vec1 {
-		var temp mcl.G2
-		mcl.G2Mul(&temp, &vec1[i], &x)
-		mcl.G2Add(&result[i], &temp, &vec2[i])
+		var temp mcl.Fr
+		mcl.FrMul(&temp, &vec1[i], &x)
+		mcl.FrAdd(&result[i], &temp, &vec2[i])
+	}
+	return result
+}
+
+// FoldedScalars returns s such that folding a vector v of size 2^l with the challenges,
+// v' = c_k v_R + v_L in round k, ends in sum s_i v_i.
+// c_k is the challenge of round k or its inverse. Round 0 splits at the most significant bit of i.
+func FoldedScalars(RandomChallenges []mcl.Fr, invert bool) []mcl.Fr {
+	l := len(RandomChallenges)
+	result := make([]mcl.Fr, 1, 1<<l)
+	result[0].SetInt64(1)
+	for k := l - 1; k >= 0; k-- {
+		c := RandomChallenges[k]
+		if invert {
+			mcl.FrInv(&c, &c)
+		}
+		m := len(result)
+		for i := 0; i < m; i++ {
+			var temp mcl.Fr
+			mcl.FrMul(&temp, &result[i], &c)
+			result = append(result, temp)
+		}
 	}
 	return result
 }
@@ -0,0 +1,244 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/alinush/go-mcl"
+)
+
+// FoldThreshold is the size below which G1Fold and G2Fold run on a single goroutine.
+var FoldThreshold = 64
+
+// foldWorkers returns the number of goroutines for a fold of size m. workers == 0 means automatic.
+func foldWorkers(workers int, m int) int {
+	if workers != 0 {
+		return workers
+	}
+	if m < FoldThreshold {
+		return 1
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// ParallelFor splits [0, m) into at most workers contiguous ranges and calls f on each range in its own goroutine.
+// It returns once all the ranges are done. With workers <= 1, f is called once on [0, m).
+func ParallelFor(workers int, m int, f func(start int, stop int)) {
+
+	if workers <= 1 || m <= 1 {
+		f(0, m)
+		return
+	}
+	step := (m + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < m; start += step {
+		stop := start + step
+		if stop > m {
+			stop = m
+		}
+		wg.Add(1)
+		go func(start int, stop int) {
+			f(start, stop)
+			wg.Done()
+		}(start, stop)
+	}
+	wg.Wait()
+}
+
+// ParallelTasks runs task(0), ..., task(n-1) with at most workers goroutines.
+func ParallelTasks(workers int, n int, task func(i int)) {
+
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			task(i)
+		}
+		return
+	}
+	if workers > n {
+		workers = n
+	}
+	tasks := make(chan int, n)
+	for i := 0; i < n; i++ {
+		tasks <- i
+	}
+	close(tasks)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			for i := range tasks {
+				task(i)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+// InnerProds computes InnerProd(A[k], B[k]) for every k with at most workers goroutines.
+// Each product is split into chunks of MillerLoopVec. The Miller loops of a product are multiplied
+// and the final exponentiation is done once per product, thus the results equal InnerProd.
+// With workers == 0, it calls InnerProd for each product. With workers == 1, it runs sequentially.
+func InnerProds(A [][]mcl.G1, B [][]mcl.G2, workers int) []mcl.GT {
+
+	n := len(A)
+	if n != len(B) {
+		panic(fmt.Sprintf("InnerProds: Error %d %d", n, len(B)))
+	}
+	result := make([]mcl.GT, n)
+	if workers <= 1 {
+		for k := range A {
+			if workers == 1 {
+				if len(A[k]) != len(B[k]) || len(A[k]) < 1 {
+					panic(fmt.Sprintf("InnerProds: Error %d %d", len(A[k]), len(B[k])))
+				}
+				result[k] = innerProdSerial(A[k], B[k])
+			} else {
+				result[k] = InnerProd(A[k], B[k])
+			}
+		}
+		return result
+	}
+
+	result, _ = innerProdsChunked(context.Background(), A, B, workers, 0)
+	return result
+}
+
+// contextChunk bounds the number of pairings between two checks of the context in InnerProdsContext.
+var contextChunk = 256
+
+// InnerProdsContext is InnerProds, but it checks ctx between chunks of at most contextChunk Miller loops.
+// It returns ctx.Err() if ctx is done before all the products are computed.
+// With workers == 0, it uses GOMAXPROCS goroutines if a product has InnerProdThreshold pairs or more.
+func InnerProdsContext(ctx context.Context, A [][]mcl.G1, B [][]mcl.G2, workers int) ([]mcl.GT, error) {
+
+	if ctx.Done() == nil {
+		// ctx can never be cancelled
+		return InnerProds(A, B, workers), nil
+	}
+	if len(A) != len(B) {
+		panic(fmt.Sprintf("InnerProdsContext: Error %d %d", len(A), len(B)))
+	}
+	if workers == 0 {
+		workers = 1
+		for k := range A {
+			if len(A[k]) >= InnerProdThreshold {
+				workers = runtime.GOMAXPROCS(0)
+				break
+			}
+		}
+	}
+	return innerProdsChunked(ctx, A, B, workers, contextChunk)
+}
+
+// innerProdsChunked splits each product in chunks, at most workers of them per product and,
+// if maxStep > 0, of at most maxStep pairs. A chunk is skipped once ctx is done.
+func innerProdsChunked(ctx context.Context, A [][]mcl.G1, B [][]mcl.G2, workers int, maxStep int) ([]mcl.GT, error) {
+
+	n := len(A)
+	if workers < 1 {
+		workers = 1
+	}
+	type chunk struct {
+		k     int
+		start int
+		stop  int
+	}
+	chunks := make([]chunk, 0, n*workers)
+	for k := range A {
+		m := len(A[k])
+		if m != len(B[k]) || m < 1 {
+			panic(fmt.Sprintf("InnerProds: Error %d %d", m, len(B[k])))
+		}
+		step := (m + workers - 1) / workers
+		if maxStep > 0 && step > maxStep {
+			step = maxStep
+		}
+		for start := 0; start < m; start += step {
+			stop := start + step
+			if stop > m {
+				stop = m
+			}
+			chunks = append(chunks, chunk{k, start, stop})
+		}
+	}
+
+	loops := make([]mcl.GT, len(chunks))
+	ParallelTasks(workers, len(chunks), func(i int) {
+		if ctx.Err() != nil {
+			return
+		}
+		c := chunks[i]
+		mcl.MillerLoopVec(&loops[i], A[c.k][c.start:c.stop], B[c.k][c.start:c.stop])
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]mcl.GT, n)
+	for k := range result {
+		result[k].SetInt64(1)
+	}
+	for i, c := range chunks {
+		mcl.GTMul(&result[c.k], &result[c.k], &loops[i])
+	}
+	ParallelTasks(workers, n, func(k int) {
+		mcl.FinalExp(&result[k], &result[k])
+	})
+	return result, nil
+}
+
+// G1MulVecWorkers computes sum scalars[i] * points[i] with the vectors split among workers goroutines.
+// Each goroutine runs one mcl.G1MulVec and the partial sums are added.
+func G1MulVecWorkers(points []mcl.G1, scalars []mcl.Fr, workers int) mcl.G1 {
+
+	m := len(points)
+	if m != len(scalars) {
+		panic(fmt.Sprintf("G1MulVecWorkers: Error %d %d", m, len(scalars)))
+	}
+	var result mcl.G1
+	if m == 0 {
+		return result
+	}
+	if workers <= 1 {
+		mcl.G1MulVec(&result, points, scalars)
+		return result
+	}
+	var mu sync.Mutex
+	ParallelFor(workers, m, func(start int, stop int) {
+		var partial mcl.G1
+		mcl.G1MulVec(&partial, points[start:stop], scalars[start:stop])
+		mu.Lock()
+		mcl.G1Add(&result, &result, &partial)
+		mu.Unlock()
+	})
+	return result
+}
+
+// G2MulVecWorkers computes sum scalars[i] * points[i] with the vectors split among workers goroutines.
+func G2MulVecWorkers(points []mcl.G2, scalars []mcl.Fr, workers int) mcl.G2 {
+
+	m := len(points)
+	if m != len(scalars) {
+		panic(fmt.Sprintf("G2MulVecWorkers: Error %d %d", m, len(scalars)))
+	}
+	var result mcl.G2
+	if m == 0 {
+		return result
+	}
+	if workers <= 1 {
+		mcl.G2MulVec(&result, points, scalars)
+		return result
+	}
+	var mu sync.Mutex
+	ParallelFor(workers, m, func(start int, stop int) {
+		var partial mcl.G2
+		mcl.G2MulVec(&partial, points[start:stop], scalars[start:stop])
+		mu.Lock()
+		mcl.G2Add(&result, &result, &partial)
+		mu.Unlock()
+	})
+	return result
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/alinush/go-mcl"
+)
+
+// PreparedVerifyingKey holds fixed G2 points, such as a KZG VK or the Q of a batch statement,
+// with their Miller-loop line coefficients (mcl.PrecomputeG2).
+// Pairing with a prepared point skips the G2 doubling and addition steps of the Miller loop.
+// The lines of Q_i are computed once, the first time Q_i is used, thus a product with a prefix of Q
+// only prepares that prefix. The key can be shared by verifiers and goroutines.
+type PreparedVerifyingKey struct {
+	Q     []mcl.G2
+	once  []sync.Once
+	lines [][]uint64
+}
+
+// NewPreparedVerifyingKey copies Q. Nothing is precomputed until the first pairing.
+func NewPreparedVerifyingKey(Q []mcl.G2) *PreparedVerifyingKey {
+	n := len(Q)
+	result := &PreparedVerifyingKey{Q: make([]mcl.G2, n), once: make([]sync.Once, n), lines: make([][]uint64, n)}
+	copy(result.Q, Q)
+	return result
+}
+
+// Matches returns true if the prepared points are Q.
+func (self *PreparedVerifyingKey) Matches(Q []mcl.G2) bool {
+	if len(self.Q) != len(Q) {
+		return false
+	}
+	for i := range Q {
+		if !self.Q[i].IsEqual(&Q[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// line returns the lines of Q_i, and computes them on first use.
+func (self *PreparedVerifyingKey) line(i int) []uint64 {
+	self.once[i].Do(func() {
+		self.lines[i] = make([]uint64, mcl.GetUint64NumToPrecompute())
+		mcl.PrecomputeG2(self.lines[i], &self.Q[i])
+	})
+	return self.lines[i]
+}
+
+// preparedWorkers follows InnerProd: a single goroutine below InnerProdThreshold.
+func preparedWorkers(m int) int {
+	if m < InnerProdThreshold {
+		return 1
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// MillerLoop computes out = prod_i MillerLoop(P_i, Q_i) with the precomputed lines.
+// P may be shorter than Q, the remaining points of Q are ignored and not prepared. Call mcl.FinalExp on out.
+func (self *PreparedVerifyingKey) MillerLoop(out *mcl.GT, P []mcl.G1) {
+
+	m := len(P)
+	if m > len(self.Q) {
+		panic("PreparedVerifyingKey: MillerLoop: Error")
+	}
+	out.SetInt64(1)
+	var mutex sync.Mutex
+	ParallelFor(preparedWorkers(m), m, func(start int, stop int) {
+		var acc, temp mcl.GT
+		acc.SetInt64(1)
+		for i := start; i < stop; i++ {
+			mcl.PrecomputedMillerLoop(&temp, &P[i], self.line(i))
+			mcl.GTMul(&acc, &acc, &temp)
+		}
+		mutex.Lock()
+		mcl.GTMul(out, out, &acc)
+		mutex.Unlock()
+	})
+}
+
+// InnerProd computes prod_i e(P_i, Q_i), i.e. utils.InnerProd(P, Q[:len(P)]).
+func (self *PreparedVerifyingKey) InnerProd(P []mcl.G1) mcl.GT {
+	var result mcl.GT
+	self.MillerLoop(&result, P)
+	mcl.FinalExp(&result, &result)
+	return result
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"time"
+)
+
+// Phase is a step of a prover, see Progress.
+type Phase int
+
+const (
+	PhaseCommit     Phase = iota // Commitment to the statement before the rounds, as T in batch
+	PhaseTransform               // Cross commitments ComL, ComR of a round
+	PhaseFiatShamir              // Challenge of a round
+	PhaseFold                    // Folding of the vectors and keys of a round
+	PhaseOpening                 // KZG openings of the final keys
+)
+
+var phaseNames = []string{"commit", "transform", "fiat-shamir", "fold", "opening"}
+
+func (self Phase) String() string {
+	if self < 0 || int(self) >= len(phaseNames) {
+		return "unknown"
+	}
+	return phaseNames[self]
+}
+
+// Progress is reported by the provers once a phase is done.
+// Round is -1 for PhaseCommit and the number of rounds for PhaseOpening.
+// M is the size of the vectors when the phase started.
+type Progress struct {
+	Round   int
+	M       uint64
+	Phase   Phase
+	Elapsed time.Duration
+}
+
+// ProgressFunc receives the Progress of a prover. It is called on the proving goroutine.
+type ProgressFunc func(Progress)
+
+// Report calls self, unless it is nil, with the time elapsed since start.
+func (self ProgressFunc) Report(round int, m uint64, phase Phase, start time.Time) {
+	if self != nil {
+		self(Progress{round, m, phase, time.Since(start)})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/bits"
+	"runtime"
 
 	"github.com/alinush/go-mcl"
 )
@@ -26,7 +27,11 @@ func IsPow2(m uint64) bool {
 	}
 }
 
+// InnerProdThreshold is the size below which InnerProd runs on a single goroutine.
+var InnerProdThreshold = 64
+
 // InnerProd computes the inner product of vector A and vector B
+// From InnerProdThreshold onwards, the Miller loops are split among GOMAXPROCS goroutines, see InnerProdWorkers.
 func InnerProd(A []mcl.G1, B []mcl.G2) mcl.GT {
 
 	m := len(A)
@@ -35,6 +40,21 @@ func InnerProd(A []mcl.G1, B []mcl.G2) mcl.GT {
 		panic(fmt.Sprintf("InnerProd: Error %d %d", m, len(B)))
 	}
 
+	workers := runtime.GOMAXPROCS(0)
+	if m < InnerProdThreshold || workers <= 1 {
+		return innerProdSerial(A, B)
+	}
+	return InnerProdWorkers(A, B, workers)
+}
+
+// InnerProdWorkers is InnerProd with the Miller loops split among workers goroutines.
+// The Fp12 results are multiplied and there is a single final exponentiation.
+func InnerProdWorkers(A []mcl.G1, B []mcl.G2, workers int) mcl.GT {
+	return InnerProds([][]mcl.G1{A}, [][]mcl.G2{B}, workers)[0]
+}
+
+func innerProdSerial(A []mcl.G1, B []mcl.G2) mcl.GT {
+
 	var prod mcl.GT
 	prod.SetInt64(1)
 	mcl.MillerLoopVec(&prod, A, B)
@@ -102,27 +122,40 @@ func FrPow(a mcl.Fr, n int64) mcl.Fr { // n has to be signed
 
 // Returns alpha, beta, G, H
 func RunMPC() (mcl.Fr, mcl.Fr, mcl.G1, mcl.G2) {
-	var alpha mcl.Fr
-	var beta mcl.Fr
-	var G mcl.G1
-	var H mcl.G2
+	return runMPC(csprng{})
+}
 
-	alpha.Random()
-	beta.Random()
-	G.Random()
-	H.Random()
+// RunMPCSeeded is RunMPC with the randomness drawn from rng.
+func RunMPCSeeded(rng *DRBG) (mcl.Fr, mcl.Fr, mcl.G1, mcl.G2) {
+	return runMPC(rng)
+}
+
+func runMPC(rng sampler) (mcl.Fr, mcl.Fr, mcl.G1, mcl.G2) {
+	alpha := rng.Fr()
+	beta := rng.Fr()
+	G := rng.G1()
+	H := rng.G2()
 
 	return alpha, beta, G, H
 }
 
 func GenerateData(m uint64) ([]mcl.G1, []mcl.G2) {
+	return generateData(csprng{}, m)
+}
+
+// GenerateDataSeeded is GenerateData with the randomness drawn from rng.
+func GenerateDataSeeded(rng *DRBG, m uint64) ([]mcl.G1, []mcl.G2) {
+	return generateData(rng, m)
+}
+
+func generateData(rng sampler, m uint64) ([]mcl.G1, []mcl.G2) {
 
 	A := make([]mcl.G1, m)
 	B := make([]mcl.G2, m)
 
 	for i := uint64(0); i < m; i++ {
-		A[i].Random()
-		B[i].Random()
+		A[i] = rng.G1()
+		B[i] = rng.G2()
 	}
 	return A, B
 }
@@ -131,21 +164,29 @@ func GenerateData(m uint64) ([]mcl.G1, []mcl.G2) {
 // This will keep Q_i's  and B_i's the same
 // This will allows us to test both batch.Verify and batch.VerifyEdrax
 func GenerateBatchingData(m uint32, n uint32) ([]mcl.G1, []mcl.G2, []mcl.G1, []mcl.G2) {
+	return generateBatchingData(csprng{}, m, n)
+}
+
+// GenerateBatchingDataSeeded is GenerateBatchingData with the randomness drawn from rng.
+func GenerateBatchingDataSeeded(rng *DRBG, m uint32, n uint32) ([]mcl.G1, []mcl.G2, []mcl.G1, []mcl.G2) {
+	return generateBatchingData(rng, m, n)
+}
+
+func generateBatchingData(rng sampler, m uint32, n uint32) ([]mcl.G1, []mcl.G2, []mcl.G1, []mcl.G2) {
 
 	var P []mcl.G1
 	var Q []mcl.G2
 	var A []mcl.G1
 	var B []mcl.G2
 
-	var b mcl.G2
-	b.Random()
+	b := rng.G2()
 
 	var a mcl.G1
 	for j := uint32(0); j < n; j++ {
 
 		var aSum mcl.G1
 		for i := uint32(0); i < m; i++ {
-			a.Random()
+			a = rng.G1()
 			A = append(A, a)
 			B = append(B, b)
 			mcl.G1Add(&aSum, &aSum, &a)
@@ -171,6 +212,18 @@ func SizeMismatchCheck(a, b uint64, msg string) {
 	}
 }
 
+// LocateFailure re-checks the n items of a batch whose combined check failed, in order.
+// It returns false and the index of the first item which fails. If every item passes, it returns false and -1:
+// the batch failed, but the culprit is not located.
+func LocateFailure(n int, check func(i int) bool) (bool, int) {
+	for i := 0; i < n; i++ {
+		if !check(i) {
+			return false, i
+		}
+	}
+	return false, -1
+}
+
 func ComputePadding(M, N uint32) (uint64, uint64) {
 	MN := NextPowOf2(uint64(N * M))
 	nDiff := uint64(uint64(math.Ceil(float64(MN)/float64(M))) - uint64(N)) // This is the size of padding for P and Q vector (gipa)
@@ -48,6 +48,19 @@ func BenchmarkUtilsInnerProd(b *testing.B) {
 	}
 }
 
+func BenchmarkUtilsInnerProdWorkers(b *testing.B) {
+
+	N := uint64(1) << 12
+	A, B := GenerateData(N)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("%d/Workers;%d", N, workers), func(b *testing.B) {
+			for bn := 0; bn < b.N; bn++ {
+				InnerProdWorkers(A, B, workers)
+			}
+		})
+	}
+}
+
 func BenchmarkUtilsValidM(b *testing.B) {
 
 	var tests = []struct {
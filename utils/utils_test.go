@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/alinush/go-mcl"
@@ -59,6 +62,186 @@ func TestInnerProd(t *testing.T) {
 	}
 }
 
+func TestInnerProds(t *testing.T) {
+
+	sizes := []int{1, 3, 37}
+	A := make([][]mcl.G1, len(sizes))
+	B := make([][]mcl.G2, len(sizes))
+	for k, m := range sizes {
+		A[k], B[k] = GenerateData(uint64(m))
+	}
+	for _, workers := range []int{0, 1, 4, 64} {
+		got := InnerProds(A, B, workers)
+		for k := range sizes {
+			want := InnerProd(A[k], B[k])
+			if !got[k].IsEqual(&want) {
+				t.Errorf("InnerProds: Product %d unequal with %d workers", k, workers)
+			}
+		}
+	}
+}
+
+func TestInnerProdsContext(t *testing.T) {
+
+	chunk := contextChunk
+	defer func() { contextChunk = chunk }()
+	contextChunk = 4
+
+	sizes := []int{1, 3, 37}
+	A := make([][]mcl.G1, len(sizes))
+	B := make([][]mcl.G2, len(sizes))
+	for k, m := range sizes {
+		A[k], B[k] = GenerateData(uint64(m))
+	}
+	want := InnerProds(A, B, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, workers := range []int{0, 1, 4} {
+		got, err := InnerProdsContext(ctx, A, B, workers)
+		if err != nil {
+			t.Fatalf("InnerProdsContext: %v", err)
+		}
+		for k := range sizes {
+			if !got[k].IsEqual(&want[k]) {
+				t.Errorf("InnerProdsContext: Product %d unequal with %d workers", k, workers)
+			}
+		}
+	}
+	cancel()
+	if _, err := InnerProdsContext(ctx, A, B, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("InnerProdsContext: Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestInnerProdThreshold(t *testing.T) {
+
+	threshold := InnerProdThreshold
+	defer func() { InnerProdThreshold = threshold }()
+
+	A, B := GenerateData(45)
+	want := innerProdSerial(A, B)
+	InnerProdThreshold = 1
+	got := InnerProd(A, B)
+	if !got.IsEqual(&want) {
+		t.Errorf("InnerProd: Parallel product differs from the serial product")
+	}
+}
+
+func TestLocateFailure(t *testing.T) {
+
+	if status, i := LocateFailure(4, func(i int) bool { return i != 2 }); status || i != 2 {
+		t.Errorf("LocateFailure: Expected false 2, got %v %d", status, i)
+	}
+	// The combined check failed but every item passes, the batch must still fail
+	if status, i := LocateFailure(4, func(i int) bool { return true }); status || i != -1 {
+		t.Errorf("LocateFailure: Expected false -1, got %v %d", status, i)
+	}
+}
+
+func TestPreparedVerifyingKey(t *testing.T) {
+
+	threshold := InnerProdThreshold
+	defer func() { InnerProdThreshold = threshold }()
+
+	A, B := GenerateData(45)
+	prepared := NewPreparedVerifyingKey(B)
+	got := prepared.InnerProd(A[:1])
+	want := InnerProd(A[:1], B[:1])
+	if !got.IsEqual(&want) {
+		t.Errorf("PreparedVerifyingKey: Single product differs from InnerProd")
+	}
+	for i := 1; i < len(B); i++ {
+		if prepared.lines[i] != nil {
+			t.Fatalf("PreparedVerifyingKey: Q_%d was prepared for a single pairing", i)
+		}
+	}
+	for _, th := range []int{64, 1} {
+		InnerProdThreshold = th
+		want = InnerProd(A, B)
+		got = prepared.InnerProd(A)
+		if !got.IsEqual(&want) {
+			t.Errorf("PreparedVerifyingKey: Product differs from InnerProd with threshold %d", th)
+		}
+		want = InnerProd(A[:3], B[:3])
+		got = prepared.InnerProd(A[:3])
+		if !got.IsEqual(&want) {
+			t.Errorf("PreparedVerifyingKey: Prefix product differs from InnerProd with threshold %d", th)
+		}
+	}
+}
+
+func TestGTCyclotomicSqr(t *testing.T) {
+
+	A, B := GenerateData(1)
+	var e, want, got mcl.GT
+	mcl.Pairing(&e, &A[0], &B[0])
+	for i := 0; i < 4; i++ {
+		mcl.GTMul(&want, &e, &e)
+		GTCyclotomicSqr(&got, &e)
+		if !got.IsEqual(&want) {
+			t.Fatalf("GTCyclotomicSqr: Square %d differs from GTMul", i)
+		}
+		e = got
+	}
+}
+
+func TestGTMultiExp(t *testing.T) {
+
+	n := 7
+	A, B := GenerateData(uint64(n))
+	bases := make([]mcl.GT, n)
+	exps := make([]mcl.Fr, n)
+	var want mcl.GT
+	want.SetInt64(1)
+	for i := range bases {
+		mcl.Pairing(&bases[i], &A[i], &B[i])
+		exps[i].Random()
+	}
+	exps[2].SetInt64(0)
+	exps[4].SetInt64(5)
+	for i := range bases {
+		var temp mcl.GT
+		mcl.GTPow(&temp, &bases[i], &exps[i])
+		mcl.GTMul(&want, &want, &temp)
+	}
+	got := GTMultiExp(bases, exps)
+	if !got.IsEqual(&want) {
+		t.Errorf("GTMultiExp: Differs from GTPow")
+	}
+	got = GTMultiExpCyclotomic(bases, exps)
+	if !got.IsEqual(&want) {
+		t.Errorf("GTMultiExpCyclotomic: Differs from GTPow")
+	}
+}
+
+func TestGTIsValid(t *testing.T) {
+
+	A, B := GenerateData(1)
+	var e mcl.GT
+	mcl.Pairing(&e, &A[0], &B[0])
+	if !GTIsValid(&e) {
+		t.Fatalf("GTIsValid: Rejected a pairing")
+	}
+
+	// An element of Fp12 outside GT
+	var x mcl.GT
+	if err := x.SetString("1 2 3 4 5 6 7 8 9 10 11 12", 10); err != nil {
+		t.Fatalf("GTIsValid: %v", err)
+	}
+	if GTIsValid(&x) {
+		t.Fatalf("GTIsValid: Accepted an element outside GT")
+	}
+	var decoded mcl.GT
+	if err := ReadGT(bytes.NewReader(x.Serialize()), &decoded); !errors.Is(err, ErrNotInGT) {
+		t.Errorf("ReadGT: Expected ErrNotInGT, got %v", err)
+	}
+	if err := GTFromHex(&decoded, GTToHex(&x)); !errors.Is(err, ErrNotInGT) {
+		t.Errorf("GTFromHex: Expected ErrNotInGT, got %v", err)
+	}
+	if err := ReadGT(bytes.NewReader(e.Serialize()), &decoded); err != nil || !decoded.IsEqual(&e) {
+		t.Errorf("ReadGT: Round trip failed: %v", err)
+	}
+}
+
 func TestFrPow(t *testing.T) {
 	N := 20
 	var alpha mcl.Fr
@@ -117,6 +300,46 @@ func TestFold(t *testing.T) {
 	if !G2SliceIsEqual(resultH, H) {
 		t.Errorf("Slice H did not match after fold")
 	}
+
+	// In place, over vec2, with several goroutines
+	G1FoldInto(G2, x, G2, G2, 4)
+	G2FoldInto(H2, x, H2, H2, 4)
+	if !G1SliceIsEqual(G2, G) {
+		t.Errorf("Slice G did not match after fold in place")
+	}
+	if !G2SliceIsEqual(H2, H) {
+		t.Errorf("Slice H did not match after fold in place")
+	}
+}
+
+func TestFoldedScalars(t *testing.T) {
+
+	l := 5
+	challenges := make([]mcl.Fr, l)
+	for i := range challenges {
+		challenges[i].Random()
+	}
+	G := make([]mcl.G1, 1<<l)
+	for i := range G {
+		G[i].Random()
+	}
+
+	for _, invert := range []bool{false, true} {
+		folded := G
+		for k := 0; k < l; k++ {
+			c := challenges[k]
+			if invert {
+				mcl.FrInv(&c, &c)
+			}
+			half := len(folded) / 2
+			folded = G1Fold(c, folded[half:], folded[:half])
+		}
+		var result mcl.G1
+		mcl.G1MulVec(&result, G, FoldedScalars(challenges, invert))
+		if !result.IsEqual(&folded[0]) {
+			t.Errorf("FoldedScalars does not match folding, invert: %v", invert)
+		}
+	}
 }
 
 func TestBatchingData(t *testing.T) {
@@ -132,3 +355,32 @@ func TestBatchingData(t *testing.T) {
 		t.Errorf("Batching data generator is an issue.")
 	}
 }
+
+func TestDRBG(t *testing.T) {
+
+	alpha1, beta1, G1, H1 := RunMPCSeeded(NewDRBG("test"))
+	alpha2, beta2, G2, H2 := RunMPCSeeded(NewDRBG("test"))
+	if !alpha1.IsEqual(&alpha2) || !beta1.IsEqual(&beta2) || !G1.IsEqual(&G2) || !H1.IsEqual(&H2) {
+		t.Errorf("Same seed gave different setups")
+	}
+	alpha3, _, _, _ := RunMPCSeeded(NewDRBG("test2"))
+	if alpha1.IsEqual(&alpha3) {
+		t.Errorf("Different seeds gave the same setup")
+	}
+
+	A1, B1 := GenerateDataSeeded(NewDRBG("test"), 16)
+	A2, B2 := GenerateDataSeeded(NewDRBG("test"), 16)
+	if !G1SliceIsEqual(A1, A2) || !G2SliceIsEqual(B1, B2) {
+		t.Errorf("Same seed gave different data")
+	}
+
+	P, Q, A, B := GenerateBatchingDataSeeded(NewDRBG("test"), 4, 8)
+	var lhs, rhs mcl.GT
+	mcl.MillerLoopVec(&lhs, P, Q)
+	mcl.FinalExp(&lhs, &lhs)
+	mcl.MillerLoopVec(&rhs, A, B)
+	mcl.FinalExp(&rhs, &rhs)
+	if !lhs.IsEqual(&rhs) {
+		t.Errorf("Seeded batching data generator is an issue.")
+	}
+}